@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/martyria/martyria/internal/config"
+	"github.com/martyria/martyria/internal/models"
+	"github.com/martyria/martyria/internal/planner"
+)
+
+func runDaily(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "plan" {
+		return fmt.Errorf("usage: martyria daily plan [-tradition T] [-from YYYY-MM-DD] [-days N] [-dry-run]")
+	}
+
+	fs := flag.NewFlagSet("daily plan", flag.ExitOnError)
+	fromStr := fs.String("from", time.Now().UTC().Format("2006-01-02"), "first day to plan")
+	days := fs.Int("days", cfg.PlannerHorizon, "number of days to plan")
+	quoteWindow := fs.Int("quote-window", cfg.PlannerQuoteWindow, "days before a quote may repeat")
+	authorWindow := fs.Int("author-window", cfg.PlannerAuthorWindow, "days before an author may repeat")
+	tradition := fs.String("tradition", string(models.DailyAll), "schedule to fill: all, orthodox, catholic, protestant or anglican")
+	dryRun := fs.Bool("dry-run", false, "print the plan without saving it")
+	fs.Parse(args[1:])
+
+	if !models.DailyTradition(*tradition).Valid() {
+		return fmt.Errorf("invalid -tradition %q", *tradition)
+	}
+
+	from, err := time.Parse("2006-01-02", *fromStr)
+	if err != nil {
+		return fmt.Errorf("invalid -from %q", *fromStr)
+	}
+
+	database, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	res, err := planner.New(database).Plan(ctx, planner.Options{
+		From:         from,
+		Days:         *days,
+		Tradition:    models.DailyTradition(*tradition),
+		QuoteWindow:  *quoteWindow,
+		AuthorWindow: *authorWindow,
+		DryRun:       *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range res.Planned {
+		reason := ""
+		if e.Reason != nil {
+			reason = "  " + *e.Reason
+		}
+		fmt.Printf("%s  quote %d%s\n", e.Date, e.QuoteID, reason)
+	}
+	fmt.Printf("%s %s..%s: %d planned (%d feasts), %d already scheduled, %d written\n",
+		res.Tradition, res.From, res.To, len(res.Planned), res.Feasts, res.Kept, res.Written)
+	return nil
+}
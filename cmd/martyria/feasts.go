@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/martyria/martyria/internal/config"
+)
+
+func runFeasts(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "backfill" {
+		return fmt.Errorf("usage: martyria feasts backfill [-force]")
+	}
+
+	fs := flag.NewFlagSet("feasts backfill", flag.ExitOnError)
+	force := fs.Bool("force", false, "re-parse every author, replacing existing rows")
+	fs.Parse(args[1:])
+
+	database, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	n, err := database.BackfillAuthorFeasts(ctx, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Parsed feasts for %d author(s)\n", n)
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/martyria/martyria/internal/config"
+	"github.com/martyria/martyria/internal/images"
+	"github.com/martyria/martyria/internal/storage"
+)
+
+func runImages(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "fetch" || len(args) > 2 {
+		return fmt.Errorf("usage: martyria images fetch [slug]")
+	}
+
+	database, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	store, err := storage.FromConfig(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("image storage: %w", err)
+	}
+	imgSvc := images.NewService(database.Pool, store)
+
+	if len(args) == 1 {
+		return imgSvc.FetchAllAuthors(ctx)
+	}
+
+	slug := args[1]
+	author, err := database.GetAuthor(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if author == nil {
+		return fmt.Errorf("author %q not found", slug)
+	}
+
+	count, err := imgSvc.FetchForAuthor(ctx, *author)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d image(s)\n", slug, count)
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/martyria/martyria/internal/api"
+	"github.com/martyria/martyria/internal/config"
+	"github.com/martyria/martyria/internal/models"
+)
+
+func runKeys(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: martyria keys create|revoke ...")
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("keys create", flag.ExitOnError)
+		name := fs.String("name", "", "user or app name (required)")
+		email := fs.String("email", "", "contact email")
+		tier := fs.String("tier", string(models.TierFree), "free, registered, unlimited or admin")
+		rateLimit := fs.Int("rate-limit", 100, "requests per hour")
+		fs.Parse(args[1:])
+
+		if *name == "" {
+			return fmt.Errorf("-name is required")
+		}
+		if !models.APIKeyTier(*tier).Valid() {
+			return fmt.Errorf("-tier must be free, registered, unlimited or admin")
+		}
+
+		raw, err := api.GenerateAPIKey()
+		if err != nil {
+			return fmt.Errorf("generate key: %w", err)
+		}
+
+		k := &models.APIKey{Name: *name, Tier: models.APIKeyTier(*tier), RateLimit: *rateLimit}
+		if *email != "" {
+			k.Email = email
+		}
+
+		database, err := connect(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		if err := database.CreateAPIKey(ctx, api.HashAPIKey(raw), k); err != nil {
+			return err
+		}
+		fmt.Printf("Created key %d for %s (tier %s, %d req/h)\n", k.ID, k.Name, k.Tier, k.RateLimit)
+		fmt.Printf("API key (shown once): %s\n", raw)
+		return nil
+
+	case "revoke":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: martyria keys revoke id")
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid key id %q", args[1])
+		}
+
+		database, err := connect(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer database.Close()
+
+		found, err := database.SetAPIKeyActive(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("key %d not found", id)
+		}
+		// Running servers notice within API_KEY_CACHE_TTL.
+		fmt.Printf("Revoked key %d\n", id)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown keys command %q (want create or revoke)", args[0])
+	}
+}
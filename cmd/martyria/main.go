@@ -0,0 +1,126 @@
+// Command martyria is the Martyria API server and admin CLI.
+//
+// Usage:
+//
+//	martyria serve
+//	martyria migrate up|down|status
+//	martyria seed [-force]
+//	martyria images fetch [slug]
+//	martyria quotes verify [-by name] [-undo] id...
+//	martyria daily plan [-tradition t] [-from date] [-days n] [-dry-run]
+//	martyria feasts backfill [-force]
+//	martyria search backfill [-force]
+//	martyria keys create|revoke ...
+//
+// Running martyria with no arguments is equivalent to "martyria serve".
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // tz= on the daily quote must work without system zoneinfo
+
+	"github.com/martyria/martyria"
+	"github.com/martyria/martyria/internal/config"
+	"github.com/martyria/martyria/internal/db"
+)
+
+const usage = `Usage: martyria <command> [arguments]
+
+Commands:
+  serve                          Run the HTTP API server (default)
+  migrate up|down|status         Apply, revert, or list schema migrations
+  seed [-force]                  Apply seed files (embedded, or SEEDS_DIR)
+  images fetch [slug]            Harvest images for one author or all missing
+  quotes verify [-by name] [-undo] id...
+                                 Mark quotes as verified (or unverified)
+  daily plan [-tradition T] [-from DATE] [-days N] [-dry-run]
+                                 Fill the daily quote schedule, favouring feasts
+  feasts backfill [-force]       Parse feast strings into author_feasts
+  search backfill [-force]       Compute search keys for Greek and Latin originals
+  keys create -name NAME [-email E] [-tier T] [-rate-limit N]
+                                 Issue an API key (printed once)
+  keys revoke id                 Deactivate an API key
+
+Configuration is read from the environment; see .env.example.
+`
+
+// command is a CLI subcommand. It receives the remaining arguments after
+// its own name.
+type command func(ctx context.Context, cfg *config.Config, args []string) error
+
+var commands = map[string]command{
+	"serve":   runServe,
+	"migrate": runMigrate,
+	"seed":    runSeed,
+	"images":  runImages,
+	"quotes":  runQuotes,
+	"daily":   runDaily,
+	"feasts":  runFeasts,
+	"search":  runSearch,
+	"keys":    runKeys,
+}
+
+func main() {
+	log.SetFlags(log.LstdFlags)
+
+	args := os.Args[1:]
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Fprint(os.Stdout, usage)
+		return
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "martyria: unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cmd(ctx, config.Load(), args); err != nil {
+		log.Fatalf("martyria %s: %v", name, err)
+	}
+}
+
+// connect opens the database pool with a bounded connect timeout.
+func connect(ctx context.Context, cfg *config.Config) (*db.DB, error) {
+	connectCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	database, err := db.New(connectCtx, cfg.DBConnString())
+	if err != nil {
+		return nil, fmt.Errorf("connect database: %w", err)
+	}
+	database.MigrationLockTimeout = cfg.MigrationLockTimeout
+	return database, nil
+}
+
+// migrationsFS returns MIGRATIONS_DIR when set, otherwise the migrations
+// embedded in the binary.
+func migrationsFS(cfg *config.Config) fs.FS {
+	if cfg.MigrationsDir != "" {
+		return os.DirFS(cfg.MigrationsDir)
+	}
+	return martyria.Migrations()
+}
+
+// seedsFS returns SEEDS_DIR when set, otherwise the embedded seeds.
+func seedsFS(cfg *config.Config) fs.FS {
+	if cfg.SeedsDir != "" {
+		return os.DirFS(cfg.SeedsDir)
+	}
+	return martyria.Seeds()
+}
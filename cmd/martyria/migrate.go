@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/martyria/martyria/internal/config"
+	"github.com/martyria/martyria/internal/db"
+)
+
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 || (args[0] != "down" && len(args) != 1) || len(args) > 2 {
+		return fmt.Errorf("usage: martyria migrate up | down [target] | status")
+	}
+
+	database, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	switch args[0] {
+	case "up":
+		return database.RunMigrations(ctx, migrationsFS(cfg))
+
+	case "down":
+		// Without a target, revert only the newest applied migration.
+		var target string
+		if len(args) == 2 {
+			target = args[1]
+		} else {
+			states, err := database.MigrationStatus(ctx, migrationsFS(cfg))
+			if err != nil {
+				return err
+			}
+			target = db.PreviousVersion(states)
+		}
+
+		reverted, err := database.MigrateDown(ctx, migrationsFS(cfg), target)
+		for _, version := range reverted {
+			fmt.Printf("Reverted %s\n", version)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No migrations to revert")
+		}
+		return nil
+
+	case "status":
+		states, err := database.MigrationStatus(ctx, migrationsFS(cfg))
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tSTATUS\tAPPLIED AT")
+		for _, st := range states {
+			status, at := "pending", ""
+			if st.Applied {
+				status = "applied"
+				at = st.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			switch {
+			case st.Missing:
+				status = "applied (file missing)"
+			case st.Modified:
+				status = "applied (modified)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", st.Version, status, at)
+		}
+		return tw.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q (want up, down or status)", args[0])
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/martyria/martyria/internal/config"
+)
+
+func runQuotes(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "verify" {
+		return fmt.Errorf("usage: martyria quotes verify [-by name] [-undo] id...")
+	}
+
+	fs := flag.NewFlagSet("quotes verify", flag.ExitOnError)
+	by := fs.String("by", os.Getenv("USER"), "reviewer recorded in verified_by")
+	undo := fs.Bool("undo", false, "clear verification instead of setting it")
+	fs.Parse(args[1:])
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("at least one quote id is required")
+	}
+	if !*undo && *by == "" {
+		return fmt.Errorf("-by is required to verify quotes")
+	}
+
+	ids := make([]int64, 0, fs.NArg())
+	for _, arg := range fs.Args() {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid quote id %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	database, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	for _, id := range ids {
+		found, err := database.SetQuoteVerified(ctx, id, !*undo, *by)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("quote %d not found", id)
+		}
+		fmt.Printf("quote %d: verified=%t\n", id, !*undo)
+	}
+	return nil
+}
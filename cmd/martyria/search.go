@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/martyria/martyria/internal/config"
+)
+
+func runSearch(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "backfill" {
+		return fmt.Errorf("usage: martyria search backfill [-force]")
+	}
+
+	fs := flag.NewFlagSet("search backfill", flag.ExitOnError)
+	force := fs.Bool("force", false, "recompute every row, e.g. after transliteration changes")
+	fs.Parse(args[1:])
+
+	database, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	n, err := database.BackfillSearchKeys(ctx, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Indexed originals of %d row(s)\n", n)
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/martyria/martyria/internal/config"
+)
+
+func runSeed(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	force := fs.Bool("force", false, "re-apply seed files that were already applied")
+	fs.Parse(args)
+
+	database, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	applied, err := database.RunSeeds(ctx, seedsFS(cfg), *force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Applied %d seed file(s)\n", len(applied))
+
+	// Seeds set feast strings directly; re-derive author_feasts from them.
+	n, err := database.BackfillAuthorFeasts(ctx, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Parsed feasts for %d author(s)\n", n)
+
+	// Likewise the search keys of quote and author originals.
+	n, err = database.BackfillSearchKeys(ctx, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Indexed originals of %d row(s)\n", n)
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/martyria/martyria/internal/api"
+	"github.com/martyria/martyria/internal/config"
+	"github.com/martyria/martyria/internal/images"
+	"github.com/martyria/martyria/internal/storage"
+)
+
+// runServe runs the HTTP API until ctx is cancelled, then drains in-flight
+// requests and background jobs before returning.
+func runServe(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	migrate := fs.Bool("migrate", cfg.MigrateOnStart, "apply pending migrations before serving")
+	fs.Parse(args)
+
+	database, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if *migrate {
+		if err := database.RunMigrations(ctx, migrationsFS(cfg)); err != nil {
+			return fmt.Errorf("run migrations: %w", err)
+		}
+	}
+
+	// Rows added by SQL (migrations, seeds) have feast strings but no
+	// author_feasts rows yet, and originals without search keys.
+	if n, err := database.BackfillAuthorFeasts(ctx, false); err != nil {
+		log.Printf("Backfill author feasts: %v", err)
+	} else if n > 0 {
+		log.Printf("Backfilled feasts for %d author(s)", n)
+	}
+	if n, err := database.BackfillSearchKeys(ctx, false); err != nil {
+		log.Printf("Backfill search keys: %v", err)
+	} else if n > 0 {
+		log.Printf("Backfilled search keys for %d row(s)", n)
+	}
+
+	store, err := storage.FromConfig(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("image storage: %w", err)
+	}
+	imgSvc := images.NewService(database.Pool, store)
+	handler := api.NewHandler(database, cfg, imgSvc)
+
+	srv := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           api.NewRouter(handler),
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Martyria %s listening on %s", cfg.Version, srv.Addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down (timeout %s)...", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting requests and drain in-flight ones first, then wait
+	// for background jobs; the pool is closed by the deferred Close.
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP shutdown: %v", err)
+	}
+	if err := handler.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Handler shutdown: %v", err)
+	}
+
+	log.Println("Shutdown complete")
+	return nil
+}
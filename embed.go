@@ -0,0 +1,34 @@
+// Package martyria holds the SQL assets compiled into the martyria binary,
+// so deployments don't need to ship the migrations/ and seeds/ folders.
+package martyria
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+//go:embed seeds/*.sql
+var seeds embed.FS
+
+// Migrations returns the embedded migrations/ directory as an fs.FS rooted
+// at the migration files.
+func Migrations() fs.FS {
+	return mustSub(migrations, "migrations")
+}
+
+// Seeds returns the embedded seeds/ directory as an fs.FS rooted at the
+// seed files.
+func Seeds() fs.FS {
+	return mustSub(seeds, "seeds")
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err) // dir is a compile-time constant matched by go:embed
+	}
+	return sub
+}
@@ -0,0 +1,316 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/martyria/martyria/internal/db"
+	"github.com/martyria/martyria/internal/models"
+)
+
+type ctxKey int
+
+const apiKeyCtxKey ctxKey = iota
+
+// HashAPIKey returns the hex SHA-256 digest stored in api_keys.key_hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAPIKey returns a new random key ("mk_" + 32 hex bytes).
+func GenerateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "mk_" + hex.EncodeToString(b), nil
+}
+
+// APIKeyFromContext returns the authenticated key, or nil for anonymous requests.
+func APIKeyFromContext(ctx context.Context) *models.APIKey {
+	k, _ := ctx.Value(apiKeyCtxKey).(*models.APIKey)
+	return k
+}
+
+// TierFromContext returns the caller's tier, or TierAnonymous.
+func TierFromContext(ctx context.Context) models.APIKeyTier {
+	if k := APIKeyFromContext(ctx); k != nil {
+		return k.Tier
+	}
+	return models.TierAnonymous
+}
+
+// APIKeyAuth authenticates requests by X-API-Key (or "Authorization: Bearer").
+// Lookups are cached in-process for cacheTTL, and last_used timestamps are
+// buffered and written in batches every flushInterval.
+type APIKeyAuth struct {
+	DB *db.DB
+
+	// AdminToken, when set, is accepted as an admin key without a database
+	// row — useful for bootstrapping before any keys exist.
+	AdminToken string
+
+	// Limiter, when set, allows each client IP LookupLimit uncached key
+	// lookups per hour, so that callers cycling through made-up keys
+	// cannot reach the database (or fill the cache) at will.
+	Limiter     Limiter
+	LookupLimit int
+	TrustProxy  bool
+
+	cacheTTL time.Duration
+
+	mu     sync.RWMutex
+	cache  map[string]cachedKey // by key hash
+	misses int                  // cached entries with a nil key
+
+	touchMu sync.Mutex
+	touched map[int64]time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type cachedKey struct {
+	key     *models.APIKey // nil caches a miss
+	expires time.Time
+}
+
+// maxCachedMisses bounds the unknown keys remembered at once; beyond it,
+// misses are looked up again rather than cached.
+const maxCachedMisses = 10000
+
+// defaultFlushInterval replaces a flush interval that is not positive.
+const defaultFlushInterval = time.Minute
+
+// NewAPIKeyAuth creates the authenticator and starts its last_used flusher.
+// Call Close to stop it.
+func NewAPIKeyAuth(database *db.DB, cacheTTL, flushInterval time.Duration) *APIKeyAuth {
+	if flushInterval <= 0 {
+		log.Printf("API key flush interval %s is not positive; using %s", flushInterval, defaultFlushInterval)
+		flushInterval = defaultFlushInterval
+	}
+	a := &APIKeyAuth{
+		DB:       database,
+		cacheTTL: cacheTTL,
+		cache:    map[string]cachedKey{},
+		touched:  map[int64]time.Time{},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go a.flushLoop(flushInterval)
+	return a
+}
+
+// Middleware attaches the caller's API key to the request context. Requests
+// without a key pass through as anonymous; unknown keys get 401 and
+// inactive keys 403.
+func (a *APIKeyAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := presentedKey(r)
+		if raw == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if a.AdminToken != "" && subtle.ConstantTimeCompare([]byte(raw), []byte(a.AdminToken)) == 1 {
+			key := &models.APIKey{Name: "admin-token", Tier: models.TierAdmin, Active: true}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyCtxKey, key)))
+			return
+		}
+
+		hash := HashAPIKey(raw)
+		key, ok := a.cached(hash)
+		var err error
+		if !ok {
+			if res, limited := a.limitLookup(r); limited {
+				setRateLimitHeaders(w, res)
+				writeRateLimited(w, res)
+				return
+			}
+			key, err = a.lookup(r.Context(), hash)
+		}
+		if err != nil {
+			log.Printf("API key lookup: %v", err)
+			writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: "failed to verify API key"})
+			return
+		}
+		if key == nil {
+			writeJSON(w, http.StatusUnauthorized, models.ErrorResponse{Error: "invalid API key"})
+			return
+		}
+		if !key.Active {
+			writeJSON(w, http.StatusForbidden, models.ErrorResponse{Error: "API key is inactive"})
+			return
+		}
+
+		a.touch(key.ID)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyCtxKey, key)))
+	})
+}
+
+// RequireAdmin rejects anonymous callers with 401 and non-admin keys with
+// 403. Wrap every mutating route with it.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		k := APIKeyFromContext(r.Context())
+		if k == nil {
+			w.Header().Set("WWW-Authenticate", `ApiKey header="X-API-Key"`)
+			writeJSON(w, http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "authentication required",
+				Message: "provide an admin API key in the X-API-Key header",
+			})
+			return
+		}
+		if k.Tier != models.TierAdmin {
+			writeJSON(w, http.StatusForbidden, models.ErrorResponse{
+				Error:   "admin access required",
+				Message: fmt.Sprintf("API key tier %q cannot modify resources", k.Tier),
+			})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Close stops the flusher and writes any pending last_used updates.
+func (a *APIKeyAuth) Close(ctx context.Context) error {
+	close(a.stop)
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return a.flush(ctx)
+}
+
+// cached returns the cached lookup result for hash, if still fresh.
+func (a *APIKeyAuth) cached(hash string) (*models.APIKey, bool) {
+	a.mu.RLock()
+	c, ok := a.cache[hash]
+	a.mu.RUnlock()
+	if ok && time.Now().Before(c.expires) {
+		return c.key, true
+	}
+	return nil, false
+}
+
+// limitLookup counts an uncached lookup against the client IP and reports
+// whether it is over LookupLimit. Limiter errors fail open.
+func (a *APIKeyAuth) limitLookup(r *http.Request) (RateLimitResult, bool) {
+	if a.Limiter == nil || a.LookupLimit <= 0 {
+		return RateLimitResult{}, false
+	}
+	res, err := a.Limiter.Allow(r.Context(), "keylookup:"+clientIP(r, a.TrustProxy), a.LookupLimit, rateLimitWindow)
+	if err != nil {
+		log.Printf("Key lookup rate limit check: %v", err)
+		return RateLimitResult{}, false
+	}
+	return res, !res.Allowed
+}
+
+// lookup fetches a key by hash and caches the result, including misses
+// while there is room for them.
+func (a *APIKeyAuth) lookup(ctx context.Context, hash string) (*models.APIKey, error) {
+	key, err := a.DB.GetAPIKeyByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if key == nil {
+		if a.misses >= maxCachedMisses {
+			a.evictExpiredLocked(now)
+		}
+		if a.misses >= maxCachedMisses {
+			return nil, nil
+		}
+	}
+	a.store(hash, cachedKey{key: key, expires: now.Add(a.cacheTTL)})
+	return key, nil
+}
+
+// store caches c under hash, keeping the miss count in step. Called with
+// a.mu held.
+func (a *APIKeyAuth) store(hash string, c cachedKey) {
+	if old, ok := a.cache[hash]; ok && old.key == nil {
+		a.misses--
+	}
+	if c.key == nil {
+		a.misses++
+	}
+	a.cache[hash] = c
+}
+
+func (a *APIKeyAuth) touch(id int64) {
+	a.touchMu.Lock()
+	a.touched[id] = time.Now()
+	a.touchMu.Unlock()
+}
+
+func (a *APIKeyAuth) flushLoop(interval time.Duration) {
+	defer close(a.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := a.flush(ctx); err != nil {
+				log.Printf("Flush API key usage: %v", err)
+			}
+			cancel()
+			a.evictExpired()
+		}
+	}
+}
+
+func (a *APIKeyAuth) flush(ctx context.Context) error {
+	a.touchMu.Lock()
+	batch := a.touched
+	a.touched = map[int64]time.Time{}
+	a.touchMu.Unlock()
+
+	return a.DB.TouchAPIKeys(ctx, batch)
+}
+
+func (a *APIKeyAuth) evictExpired() {
+	a.mu.Lock()
+	a.evictExpiredLocked(time.Now())
+	a.mu.Unlock()
+}
+
+func (a *APIKeyAuth) evictExpiredLocked(now time.Time) {
+	for hash, c := range a.cache {
+		if now.After(c.expires) {
+			if c.key == nil {
+				a.misses--
+			}
+			delete(a.cache, hash)
+		}
+	}
+}
+
+func presentedKey(r *http.Request) string {
+	if k := strings.TrimSpace(r.Header.Get("X-API-Key")); k != "" {
+		return k
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
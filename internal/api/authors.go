@@ -0,0 +1,157 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/martyria/martyria/internal/db"
+	"github.com/martyria/martyria/internal/models"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CreateAuthor handles POST /v1/authors.
+func (h *Handler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
+	in := models.AuthorInput{CopyrightStatus: models.CopyrightPublicDomain}
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	if errs := validateAuthor(&in); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	author, err := h.DB.CreateAuthor(r.Context(), in)
+	if errors.Is(err, db.ErrSlugTaken) {
+		writeJSON(w, http.StatusConflict, models.ErrorResponse{Error: "slug already in use", Message: in.Slug})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.Header().Set("Location", "/v1/authors/"+author.Slug)
+	writeJSON(w, http.StatusCreated, author)
+}
+
+// UpdateAuthor handles PATCH /v1/authors/{slug}. Only fields present in the
+// body are changed; send null to clear an optional field.
+func (h *Handler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	author, err := h.DB.GetAuthor(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if author == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "author not found"})
+		return
+	}
+
+	in := author.Input()
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	if in.Slug == "" {
+		in.Slug = author.Slug
+	}
+	if errs := validateAuthor(&in); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	updated, err := h.DB.UpdateAuthor(r.Context(), author.ID, in)
+	if errors.Is(err, db.ErrSlugTaken) {
+		writeJSON(w, http.StatusConflict, models.ErrorResponse{Error: "slug already in use", Message: in.Slug})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if updated == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "author not found"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteAuthor handles DELETE /v1/authors/{slug}. Authors with quotes or
+// permission records are only removed with ?cascade=true.
+func (h *Handler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	author, err := h.DB.GetAuthor(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if author == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "author not found"})
+		return
+	}
+
+	cascade := queryParam(r, "cascade", "") == "true"
+	deleted, err := h.DB.DeleteAuthor(r.Context(), author.ID, cascade)
+	if errors.Is(err, db.ErrAuthorHasDependents) {
+		writeJSON(w, http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "retry with ?cascade=true to delete them as well",
+		})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !deleted {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "author not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateAuthor trims in and returns per-field errors.
+func validateAuthor(in *models.AuthorInput) map[string]string {
+	errs := map[string]string{}
+
+	in.Slug = strings.TrimSpace(in.Slug)
+	in.Name = strings.TrimSpace(in.Name)
+
+	if in.Name == "" {
+		errs["name"] = "required"
+	}
+	if in.Slug != "" && !slugPattern.MatchString(in.Slug) {
+		errs["slug"] = "must be lowercase letters, digits and single hyphens"
+	}
+	if !in.Era.Valid() {
+		errs["era"] = "must be one of apostolic, ante_nicene, nicene, post_nicene, medieval, reformation, modern, contemporary"
+	}
+	if !in.Tradition.Valid() {
+		errs["tradition"] = "must be one of pre_schism, orthodox, catholic, protestant, anglican, non_denominational"
+	}
+	if !in.CopyrightStatus.Valid() {
+		errs["copyright_status"] = "must be one of public_domain, short_quote_fair_use, permission_granted, cc_by_sa"
+	}
+	if in.BornYear != nil && in.DiedYear != nil && *in.DiedYear < *in.BornYear {
+		errs["died_year"] = "must not be before born_year"
+	}
+	if in.CanonizedDate != nil {
+		if _, err := time.Parse("2006-01-02", *in.CanonizedDate); err != nil {
+			errs["canonized_date"] = "must be a date in YYYY-MM-DD format"
+		}
+	}
+	return errs
+}
+
+func writeValidationErrors(w http.ResponseWriter, errs map[string]string) {
+	writeJSON(w, http.StatusUnprocessableEntity, models.ErrorResponse{
+		Error:  "validation failed",
+		Fields: errs,
+	})
+}
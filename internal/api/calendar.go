@@ -0,0 +1,178 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martyria/martyria/internal/calendar"
+	"github.com/martyria/martyria/internal/db"
+	"github.com/martyria/martyria/internal/models"
+)
+
+// The feed lists icsDefaultYears years of occurrences unless ?years= asks
+// for more, up to icsMaxYears.
+const (
+	icsDefaultYears = 2
+	icsMaxYears     = 10
+)
+
+// CalendarDay handles GET /v1/calendar/{date}?tradition=, listing the
+// authors whose feast falls on the civil date. tradition is all (the
+// default), orthodox or catholic.
+func (h *Handler) CalendarDay(w http.ResponseWriter, r *http.Request) {
+	date, err := time.Parse(dateLayout, r.PathValue("date"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid date", Message: "use YYYY-MM-DD"})
+		return
+	}
+	tradition := queryParam(r, "tradition", "all")
+	if tradition != "all" && tradition != "orthodox" && tradition != "catholic" {
+		writeValidationErrors(w, map[string]string{"tradition": "must be one of all, orthodox, catholic"})
+		return
+	}
+
+	filter := tradition
+	if filter == "all" {
+		filter = ""
+	}
+	comms, err := h.DB.FeastsOn(r.Context(), date, filter)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	jy, jm, jd := calendar.GregorianToJulian(date)
+	day := models.CalendarDay{
+		Date:           date.Format(dateLayout),
+		JulianDate:     fmt.Sprintf("%04d-%02d-%02d", jy, jm, jd),
+		Tradition:      tradition,
+		OrthodoxPascha: calendar.OrthodoxPascha(date.Year()).Format(dateLayout),
+		WesternPascha:  calendar.WesternPascha(date.Year()).Format(dateLayout),
+		Commemorations: comms,
+	}
+
+	writeJSON(w, http.StatusOK, day)
+}
+
+// CalendarFeed handles GET /v1/calendar.ics, an RFC 5545 feed of authors'
+// feasts filtered by ?tradition= (all, orthodox, catholic), ?era= and
+// ?authors=slug,slug. Every occurrence from ?year= (default this year) for
+// ?years= years is its own all-day event: Julian-calendar and movable
+// feasts do not keep a fixed Gregorian date, so an RRULE cannot describe
+// them.
+func (h *Handler) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	errs := map[string]string{}
+	tradition := queryParam(r, "tradition", "all")
+	if tradition != "all" && tradition != "orthodox" && tradition != "catholic" {
+		errs["tradition"] = "must be one of all, orthodox, catholic"
+	}
+	era := queryParam(r, "era", "")
+	if era != "" && !models.AuthorEra(era).Valid() {
+		errs["era"] = "unknown era"
+	}
+	year, err := strconv.Atoi(queryParam(r, "year", strconv.Itoa(time.Now().Year())))
+	if err != nil || year < 1 || year > 9999 {
+		errs["year"] = "must be a year between 1 and 9999"
+	}
+	years, err := strconv.Atoi(queryParam(r, "years", strconv.Itoa(icsDefaultYears)))
+	if err != nil || years < 1 || years > icsMaxYears {
+		errs["years"] = fmt.Sprintf("must be between 1 and %d", icsMaxYears)
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	f := db.FeastFilter{Era: era}
+	if tradition != "all" {
+		f.Tradition = tradition
+	}
+	for _, slug := range strings.Split(queryParam(r, "authors", ""), ",") {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			f.Slugs = append(f.Slugs, slug)
+		}
+	}
+
+	feasts, err := h.DB.ListFeasts(r.Context(), f)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	host := "martyria"
+	if u, err := url.Parse(h.Config.BaseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	var events []calendar.Event
+	for _, e := range feasts {
+		for y := year; y < year+years; y++ {
+			for _, date := range e.Feast.Dates(y) {
+				events = append(events, h.feastEvent(e, date, host, tradition == "all"))
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="martyria-feasts.ics"`)
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if err := calendar.WriteICS(w, "Martyria feast days", events); err != nil {
+		log.Printf("Write calendar feed: %v", err)
+	}
+}
+
+// feastEvent describes one occurrence of a feast. withTradition labels the
+// summary when the feed mixes traditions.
+func (h *Handler) feastEvent(e db.FeastEntry, date time.Time, host string, withTradition bool) calendar.Event {
+	a := &e.Author
+	summary := models.FeastTitle(a.Name, a.Canonized, e.Rank)
+	label := "Orthodox"
+	if e.Tradition == "catholic" {
+		label = "Catholic"
+	}
+	if withTradition {
+		summary += " (" + label + ")"
+	}
+
+	var desc []string
+	if a.BioShort != nil {
+		desc = append(desc, *a.BioShort)
+	}
+	switch f := e.Feast; {
+	case f.Movable && f.PaschaOffset < 0:
+		desc = append(desc, fmt.Sprintf("Kept %d days before Pascha.", -f.PaschaOffset))
+	case f.Movable:
+		desc = append(desc, fmt.Sprintf("Kept %d days after Pascha.", f.PaschaOffset))
+	case f.Calendar == calendar.Julian:
+		desc = append(desc, fmt.Sprintf("Kept on %s %d of the Julian calendar.", f.Month, f.Day))
+	}
+	if q := e.Quote; q != nil {
+		cite := "— " + a.Name
+		if q.SourceWork != nil {
+			cite += ", " + *q.SourceWork
+		}
+		quote := "\u201c" + q.Text + "\u201d\n" + cite
+		if q.Attribution != nil {
+			quote += "\n(" + *q.Attribution + ")"
+		}
+		desc = append(desc, quote)
+	}
+
+	// The UID is built from stable data, not the author_feasts row ID:
+	// rows are recreated whenever the author's feasts are re-derived.
+	return calendar.Event{
+		UID:         fmt.Sprintf("feast-%s-%s-%s-%s@%s", a.Slug, e.Tradition, e.Rank, date.Format("20060102"), host),
+		Date:        date,
+		Stamp:       a.UpdatedAt,
+		Summary:     summary,
+		Description: strings.Join(desc, "\n\n"),
+		URL:         h.Config.BaseURL + "/v1/authors/" + a.Slug,
+		Categories:  []string{label},
+	}
+}
@@ -2,16 +2,20 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/martyria/martyria/internal/calendar"
 	"github.com/martyria/martyria/internal/config"
 	"github.com/martyria/martyria/internal/db"
 	"github.com/martyria/martyria/internal/images"
 	"github.com/martyria/martyria/internal/models"
+	"github.com/martyria/martyria/internal/planner"
 )
 
 // Handler holds dependencies for all HTTP handlers.
@@ -19,10 +23,95 @@ type Handler struct {
 	DB       *db.DB
 	Config   *config.Config
 	ImageSvc *images.Service
+	Planner  *planner.Planner
+	Auth     *APIKeyAuth
+	Limiter  Limiter
+
+	// Background work (e.g. batch image fetches) outlives the request
+	// that started it; Shutdown waits for it to drain.
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+	bgWG     sync.WaitGroup
+
+	// Cancel funcs for jobs running in this process, by job ID.
+	jobsMu     sync.Mutex
+	jobCancels map[int64]context.CancelFunc
 }
 
 func NewHandler(database *db.DB, cfg *config.Config, imgSvc *images.Service) *Handler {
-	return &Handler{DB: database, Config: cfg, ImageSvc: imgSvc}
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	h := &Handler{
+		DB:       database,
+		Config:   cfg,
+		ImageSvc: imgSvc,
+		Planner:  planner.New(database),
+		Auth:     NewAPIKeyAuth(database, cfg.APIKeyCacheTTL, cfg.APIKeyFlushInterval),
+		Limiter:  NewLimiter(cfg.RedisURL),
+		bgCtx:    bgCtx,
+		bgCancel: bgCancel,
+
+		jobCancels: map[int64]context.CancelFunc{},
+	}
+	h.Auth.AdminToken = cfg.AdminToken
+	h.Auth.Limiter, h.Auth.LookupLimit, h.Auth.TrustProxy = h.Limiter, cfg.RateLimitAnonymous, cfg.TrustProxy
+	return h
+}
+
+// shutdownGrace bounds how long Shutdown keeps waiting once ctx has
+// expired: for cancelled background work to unwind, and for the final
+// flush of API key usage.
+const shutdownGrace = 5 * time.Second
+
+// Shutdown waits for background work to finish, flushes pending API key
+// usage and closes the rate limiter. If ctx expires first, the background
+// context is cancelled and Shutdown waits up to shutdownGrace more for it
+// to unwind, so that jobs can record their final status before the
+// database is closed. The flush and close always run; their errors are
+// returned together with ctx.Err().
+func (h *Handler) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.bgWG.Wait()
+		close(done)
+	}()
+
+	var errs []error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+		h.bgCancel()
+		select {
+		case <-done:
+		case <-time.After(shutdownGrace):
+			errs = append(errs, errors.New("background work still running after cancellation"))
+		}
+	}
+	h.bgCancel()
+
+	flushCtx := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		flushCtx, cancel = context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+	}
+	if err := h.Auth.Close(flushCtx); err != nil {
+		errs = append(errs, fmt.Errorf("flush api key usage: %w", err))
+	}
+	if err := h.Limiter.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close rate limiter: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// goBackground runs fn in a tracked goroutine bound to the handler's
+// background context.
+func (h *Handler) goBackground(fn func(ctx context.Context)) {
+	h.bgWG.Add(1)
+	go func() {
+		defer h.bgWG.Done()
+		fn(h.bgCtx)
+	}()
 }
 
 // --- Health ---
@@ -44,10 +133,31 @@ func (h *Handler) ListAuthors(w http.ResponseWriter, r *http.Request) {
 		Era:       queryParam(r, "era", ""),
 		Tradition: queryParam(r, "tradition", ""),
 		Search:    queryParam(r, "search", ""),
+		Translit:  queryParam(r, "translit", "") == "true",
 		Page:      page,
 		PerPage:   perPage,
 	}
 
+	// ?feast=MM-DD, optionally with ?calendar=julian|gregorian
+	errs := map[string]string{}
+	if feast := queryParam(r, "feast", ""); feast != "" {
+		d, err := time.Parse("01-02", feast)
+		if err != nil {
+			errs["feast"] = "must be a month and day in MM-DD format"
+		}
+		f.FeastMonth, f.FeastDay = int(d.Month()), d.Day()
+	}
+	if cal := calendar.Calendar(queryParam(r, "calendar", "")); cal != "" {
+		if !cal.Valid() {
+			errs["calendar"] = "must be julian or gregorian"
+		}
+		f.FeastCalendar = string(cal)
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
 	authors, total, err := h.DB.ListAuthors(r.Context(), f)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{
@@ -85,9 +195,10 @@ func (h *Handler) GetAuthor(w http.ResponseWriter, r *http.Request) {
 
 	// Attach primary image
 	img, _ := h.DB.GetPrimaryImage(r.Context(), author.ID)
-	if img != nil {
-		imageURL := fmt.Sprintf("%s/data/images/%s", h.Config.BaseURL, img.LocalPath)
-		author.ImageURL = &imageURL
+	if img != nil && img.LocalPath != nil {
+		if imageURL := h.imageURL(r.Context(), *img.LocalPath); imageURL != "" {
+			author.ImageURL = &imageURL
+		}
 	}
 
 	writeJSON(w, http.StatusOK, author)
@@ -109,6 +220,7 @@ func (h *Handler) GetAuthorQuotes(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
 	}
+	redactQuotes(r.Context(), quotes)
 
 	writeJSON(w, http.StatusOK, models.PaginatedResponse{
 		Data:       quotes,
@@ -131,6 +243,9 @@ func (h *Handler) ListQuotes(w http.ResponseWriter, r *http.Request) {
 		Era:        queryParam(r, "era", ""),
 		Tradition:  queryParam(r, "tradition", ""),
 		Language:   queryParam(r, "language", ""),
+		Search:     queryParam(r, "q", ""),
+		Translit:   queryParam(r, "translit", "") == "true",
+		Tier:       TierFromContext(r.Context()),
 		Page:       page,
 		PerPage:    perPage,
 	}
@@ -146,6 +261,7 @@ func (h *Handler) ListQuotes(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
 	}
+	redactQuotes(r.Context(), quotes)
 
 	writeJSON(w, http.StatusOK, models.PaginatedResponse{
 		Data:       quotes,
@@ -173,6 +289,7 @@ func (h *Handler) GetQuote(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "quote not found"})
 		return
 	}
+	redactQuote(r.Context(), quote)
 
 	writeJSON(w, http.StatusOK, quote)
 }
@@ -194,21 +311,46 @@ func (h *Handler) RandomQuote(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "no quotes found"})
 		return
 	}
+	redactQuote(r.Context(), quote)
 
 	writeJSON(w, http.StatusOK, quote)
 }
 
+// DailyQuote handles GET /v1/quotes/daily. "Today" is the date in ?tz=
+// (an IANA zone, default DAILY_TIMEZONE), and ?tradition= picks the
+// schedule.
 func (h *Handler) DailyQuote(w http.ResponseWriter, r *http.Request) {
-	dateStr := queryParam(r, "date", "")
-	date := time.Now()
-	if dateStr != "" {
+	tradition, ok := dailyTradition(r)
+	if !ok {
+		writeValidationErrors(w, map[string]string{"tradition": traditionError})
+		return
+	}
+	tz := queryParam(r, "tz", h.Config.DailyTimezone)
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		writeValidationErrors(w, map[string]string{"tz": "unknown time zone"})
+		return
+	}
+
+	today := time.Now().In(loc)
+	date := today
+	if dateStr := queryParam(r, "date", ""); dateStr != "" {
 		parsed, err := time.Parse("2006-01-02", dateStr)
-		if err == nil {
-			date = parsed
+		if err != nil {
+			writeValidationErrors(w, map[string]string{"date": "must be a date in YYYY-MM-DD format"})
+			return
 		}
+		date = parsed
 	}
 
-	quote, reason, err := h.DB.GetDailyQuote(r.Context(), date)
+	// Pin today's fallback pick once it has been served; future dates stay
+	// open for the schedule and planner. Only admins pin past dates, so a
+	// public read of an arbitrary date never writes.
+	dateStr, todayStr := date.Format("2006-01-02"), today.Format("2006-01-02")
+	persist := dateStr == todayStr ||
+		(dateStr < todayStr && TierFromContext(r.Context()) == models.TierAdmin)
+
+	quote, reason, err := h.DB.GetDailyQuote(r.Context(), date, tradition, persist)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
@@ -217,10 +359,13 @@ func (h *Handler) DailyQuote(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "no daily quote available"})
 		return
 	}
+	redactQuote(r.Context(), quote)
 
 	resp := models.QuoteOfTheDay{
-		Quote: *quote,
-		Date:  date.Format("2006-01-02"),
+		Quote:     *quote,
+		Date:      dateStr,
+		Tradition: tradition,
+		Timezone:  loc.String(),
 	}
 	if reason != nil {
 		resp.Reason = *reason
@@ -257,6 +402,7 @@ func (h *Handler) GetTopicQuotes(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
 	}
+	redactQuotes(r.Context(), quotes)
 
 	writeJSON(w, http.StatusOK, models.PaginatedResponse{
 		Data:       quotes,
@@ -291,36 +437,67 @@ func (h *Handler) GetAuthorImages(w http.ResponseWriter, r *http.Request) {
 	// Populate URLs
 	for i := range imgs {
 		if imgs[i].LocalPath != nil {
-			u := fmt.Sprintf("%s/data/images/%s", h.Config.BaseURL, *imgs[i].LocalPath)
-			imgs[i].FullURL = u
+			imgs[i].FullURL = h.imageURL(r.Context(), *imgs[i].LocalPath)
 		}
 		if imgs[i].ThumbnailPath != nil {
-			u := fmt.Sprintf("%s/data/images/%s", h.Config.BaseURL, *imgs[i].ThumbnailPath)
-			imgs[i].ThumbnailURL = u
+			imgs[i].ThumbnailURL = h.imageURL(r.Context(), *imgs[i].ThumbnailPath)
 		}
 	}
 
 	writeJSON(w, http.StatusOK, imgs)
 }
 
+// FetchAllImages starts a tracked harvest job for every author without
+// images and returns its ID. Only one full harvest may run at a time.
 func (h *Handler) FetchAllImages(w http.ResponseWriter, r *http.Request) {
 	if h.ImageSvc == nil {
 		writeJSON(w, http.StatusServiceUnavailable, models.ErrorResponse{Error: "image service not configured"})
 		return
 	}
 
-	go func() {
-		if err := h.ImageSvc.FetchAllAuthors(context.Background()); err != nil {
-			log.Printf("Batch image fetch error: %v", err)
-		}
-	}()
+	var createdBy *string
+	if k := APIKeyFromContext(r.Context()); k != nil {
+		createdBy = &k.Name
+	}
+
+	job, err := h.DB.CreateJob(r.Context(), models.JobKindImageHarvest, createdBy)
+	if errors.Is(err, db.ErrJobRunning) {
+		writeJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":      "an image harvest is already running",
+			"job_id":     job.ID,
+			"status_url": h.jobURL(job.ID),
+		})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.startHarvestJob(job.ID)
 
-	writeJSON(w, http.StatusAccepted, map[string]string{
-		"status":  "accepted",
-		"message": "Image fetch started for all authors without images",
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"status":     "accepted",
+		"message":    "Image fetch started for all authors without images",
+		"job_id":     job.ID,
+		"status_url": h.jobURL(job.ID),
 	})
 }
 
+// imageURL resolves a storage key to a client URL via the configured
+// backend, or "" if it cannot be resolved.
+func (h *Handler) imageURL(ctx context.Context, key string) string {
+	if h.ImageSvc == nil {
+		return ""
+	}
+	u, err := h.ImageSvc.Storage.URL(ctx, key)
+	if err != nil {
+		log.Printf("Image URL for %s: %v", key, err)
+		return ""
+	}
+	return u
+}
+
 func (h *Handler) FetchAuthorImages(w http.ResponseWriter, r *http.Request) {
 	slug := r.PathValue("slug")
 	if h.ImageSvc == nil {
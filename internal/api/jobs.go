@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/martyria/martyria/internal/images"
+	"github.com/martyria/martyria/internal/models"
+)
+
+// errJobCancelled stops a harvest when cancellation was requested through
+// the database (possibly from another replica).
+var errJobCancelled = errors.New("job cancelled")
+
+// GetJob returns a job's state, progress and per-author results.
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid job id"})
+		return
+	}
+
+	job, err := h.DB.GetJob(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if job == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "job not found"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// CancelJob requests cancellation of a running job. The worker stops after
+// the author it is currently processing.
+func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid job id"})
+		return
+	}
+
+	job, err := h.DB.RequestJobCancel(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if job == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "job not found"})
+		return
+	}
+	if job.State != models.JobRunning {
+		writeJSON(w, http.StatusConflict, models.ErrorResponse{
+			Error: fmt.Sprintf("job is already %s", job.State),
+		})
+		return
+	}
+
+	// Interrupt immediately if the job runs in this process; otherwise its
+	// replica sees cancel_requested at the next progress update.
+	h.jobsMu.Lock()
+	if cancel, ok := h.jobCancels[id]; ok {
+		cancel()
+	}
+	h.jobsMu.Unlock()
+
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// startHarvestJob runs a full image harvest for job id in the background,
+// recording per-author results as it goes.
+func (h *Handler) startHarvestJob(id int64) {
+	h.goBackground(func(bgCtx context.Context) {
+		ctx, cancel := context.WithCancel(bgCtx)
+		defer cancel()
+
+		h.jobsMu.Lock()
+		h.jobCancels[id] = cancel
+		h.jobsMu.Unlock()
+		defer func() {
+			h.jobsMu.Lock()
+			delete(h.jobCancels, id)
+			h.jobsMu.Unlock()
+		}()
+
+		err := h.runHarvest(ctx, id)
+
+		// Record the outcome even if ctx was cancelled.
+		state, errMsg := models.JobSucceeded, (*string)(nil)
+		switch {
+		case errors.Is(err, errJobCancelled) || (err != nil && ctx.Err() != nil && bgCtx.Err() == nil):
+			state = models.JobCancelled
+		case err != nil:
+			state = models.JobFailed
+			msg := err.Error()
+			if bgCtx.Err() != nil {
+				msg = "interrupted by server shutdown"
+			}
+			errMsg = &msg
+		}
+
+		finishCtx, finishCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer finishCancel()
+		if err := h.DB.FinishJob(finishCtx, id, state, errMsg); err != nil {
+			log.Printf("Job %d: %v", id, err)
+		}
+		log.Printf("Job %d finished: %s", id, state)
+	})
+}
+
+func (h *Handler) runHarvest(ctx context.Context, id int64) error {
+	authors, err := h.ImageSvc.AuthorsWithoutImages(ctx)
+	if err != nil {
+		return err
+	}
+	if err := h.DB.SetJobTotal(ctx, id, len(authors)); err != nil {
+		return err
+	}
+
+	return h.ImageSvc.Harvest(ctx, authors, func(res images.AuthorResult) error {
+		result := models.JobResult{AuthorID: res.Author.ID, ImagesStored: res.Stored}
+		if res.Err != nil {
+			msg := res.Err.Error()
+			result.Error = &msg
+		}
+
+		cancelRequested, err := h.DB.RecordJobResult(ctx, id, result)
+		if err != nil {
+			return err
+		}
+		if cancelRequested {
+			return errJobCancelled
+		}
+		return nil
+	})
+}
+
+func (h *Handler) jobURL(id int64) string {
+	return fmt.Sprintf("%s/v1/jobs/%d", h.Config.BaseURL, id)
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martyria/martyria/internal/models"
+)
+
+// ListPermissions handles GET /v1/authors/{slug}/permissions.
+func (h *Handler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	author, err := h.DB.GetAuthor(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if author == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "author not found"})
+		return
+	}
+
+	perms, err := h.DB.ListPermissions(r.Context(), author.ID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, perms)
+}
+
+// CreatePermission handles POST /v1/authors/{slug}/permissions.
+func (h *Handler) CreatePermission(w http.ResponseWriter, r *http.Request) {
+	author, err := h.DB.GetAuthor(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if author == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "author not found"})
+		return
+	}
+
+	in := models.PermissionInput{Status: models.PermissionPending}
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	if errs := validatePermission(&in); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	perm, err := h.DB.CreatePermission(r.Context(), author.ID, in)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.Header().Set("Location", "/v1/permissions/"+strconv.FormatInt(perm.ID, 10))
+	writeJSON(w, http.StatusCreated, perm)
+}
+
+// UpdatePermission handles PATCH /v1/permissions/{id}, e.g. to record a
+// response. Only fields present in the body are changed.
+func (h *Handler) UpdatePermission(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid permission id"})
+		return
+	}
+
+	perm, err := h.DB.GetPermission(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if perm == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "permission not found"})
+		return
+	}
+
+	in := perm.Input()
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	if errs := validatePermission(&in); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	perm, err = h.DB.UpdatePermission(r.Context(), id, in)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if perm == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "permission not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, perm)
+}
+
+// DeletePermission handles DELETE /v1/permissions/{id}.
+func (h *Handler) DeletePermission(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid permission id"})
+		return
+	}
+
+	deleted, err := h.DB.DeletePermission(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !deleted {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "permission not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validatePermission(in *models.PermissionInput) map[string]string {
+	errs := map[string]string{}
+
+	in.Organization = strings.TrimSpace(in.Organization)
+
+	if in.Organization == "" {
+		errs["organization"] = "required"
+	}
+	if !in.Status.Valid() {
+		errs["status"] = "must be one of pending, granted, denied, no_response"
+	}
+
+	var contacted, responded time.Time
+	if in.DateContacted != nil {
+		var err error
+		if contacted, err = time.Parse("2006-01-02", *in.DateContacted); err != nil {
+			errs["date_contacted"] = "must be a date in YYYY-MM-DD format"
+		}
+	}
+	if in.DateResponded != nil {
+		var err error
+		if responded, err = time.Parse("2006-01-02", *in.DateResponded); err != nil {
+			errs["date_responded"] = "must be a date in YYYY-MM-DD format"
+		}
+	}
+	if !contacted.IsZero() && !responded.IsZero() && responded.Before(contacted) {
+		errs["date_responded"] = "must not be before date_contacted"
+	}
+	return errs
+}
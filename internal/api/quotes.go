@@ -0,0 +1,365 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/martyria/martyria/internal/db"
+	"github.com/martyria/martyria/internal/models"
+)
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// restrictedQuoteWords is how much of a restricted quote public callers see.
+const restrictedQuoteWords = 30
+
+// CreateQuote handles POST /v1/quotes.
+func (h *Handler) CreateQuote(w http.ResponseWriter, r *http.Request) {
+	in := models.QuoteInput{Language: "en", License: "public_domain"}
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	if errs := validateQuote(&in); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	id, err := h.DB.CreateQuote(r.Context(), in, callerName(r.Context()))
+	if writeQuoteWriteError(w, err) {
+		return
+	}
+
+	quote, err := h.DB.GetQuote(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.Header().Set("Location", "/v1/quotes/"+strconv.FormatInt(id, 10))
+	writeJSON(w, http.StatusCreated, quote)
+}
+
+// UpdateQuote handles PATCH /v1/quotes/{id}. Only fields present in the body
+// are changed; "topics" replaces the whole topic list.
+func (h *Handler) UpdateQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid quote id"})
+		return
+	}
+
+	quote, err := h.DB.GetQuote(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if quote == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "quote not found"})
+		return
+	}
+
+	in := quote.Input()
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	if errs := validateQuote(&in); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	found, err := h.DB.UpdateQuote(r.Context(), id, in, callerName(r.Context()))
+	if writeQuoteWriteError(w, err) {
+		return
+	}
+	if !found {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "quote not found"})
+		return
+	}
+
+	quote, err = h.DB.GetQuote(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, quote)
+}
+
+// DeleteQuote handles DELETE /v1/quotes/{id}.
+func (h *Handler) DeleteQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid quote id"})
+		return
+	}
+
+	deleted, err := h.DB.DeleteQuote(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !deleted {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "quote not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyQuote handles POST /v1/quotes/{id}/verify, recording the calling
+// key as reviewer.
+func (h *Handler) VerifyQuote(w http.ResponseWriter, r *http.Request) {
+	h.setQuoteVerified(w, r, true)
+}
+
+// UnverifyQuote handles POST /v1/quotes/{id}/unverify, returning the quote
+// to the review queue.
+func (h *Handler) UnverifyQuote(w http.ResponseWriter, r *http.Request) {
+	h.setQuoteVerified(w, r, false)
+}
+
+func (h *Handler) setQuoteVerified(w http.ResponseWriter, r *http.Request, verified bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid quote id"})
+		return
+	}
+
+	found, err := h.DB.SetQuoteVerified(r.Context(), id, verified, callerName(r.Context()))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !found {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "quote not found"})
+		return
+	}
+
+	quote, err := h.DB.GetQuote(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, quote)
+}
+
+// ReviewQueue handles GET /v1/review/queue: unverified quotes, oldest first.
+func (h *Handler) ReviewQueue(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(queryParam(r, "page", "1"))
+	perPage, _ := strconv.Atoi(queryParam(r, "per_page", "20"))
+
+	unverified := false
+	f := models.QuoteFilter{
+		AuthorSlug: queryParam(r, "author", ""),
+		Verified:   &unverified,
+		Sort:       models.SortOldestFirst,
+		Page:       page,
+		PerPage:    perPage,
+	}
+
+	quotes, total, err := h.DB.ListQuotes(r.Context(), f)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "failed to list review queue",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.PaginatedResponse{
+		Data:       quotes,
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: int64(db.TotalPages(total, perPage)),
+	})
+}
+
+// AddQuoteSources handles POST /v1/quotes/{id}/sources. The body is one
+// source object or an array of them; all are added or none.
+func (h *Handler) AddQuoteSources(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid quote id"})
+		return
+	}
+
+	var raw json.RawMessage
+	if err := decodeJSON(w, r, &raw); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	var in []models.QuoteSourceInput
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = strictUnmarshal(raw, &in)
+	} else {
+		in = make([]models.QuoteSourceInput, 1)
+		err = strictUnmarshal(raw, &in[0])
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	if len(in) == 0 {
+		writeValidationErrors(w, map[string]string{"sources": "at least one source is required"})
+		return
+	}
+
+	errs := map[string]string{}
+	for i := range in {
+		for field, msg := range validateQuoteSource(&in[i]) {
+			errs[fmt.Sprintf("%d.%s", i, field)] = msg
+		}
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	added, err := h.DB.AddQuoteSources(r.Context(), id, in)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if added == nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "quote not found"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, added)
+}
+
+// DeleteQuoteSource handles DELETE /v1/quotes/{id}/sources/{source_id}.
+func (h *Handler) DeleteQuoteSource(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid quote id"})
+		return
+	}
+	sourceID, err := strconv.ParseInt(r.PathValue("source_id"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid source id"})
+		return
+	}
+
+	deleted, err := h.DB.DeleteQuoteSource(r.Context(), id, sourceID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !deleted {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "source not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateQuoteSource(in *models.QuoteSourceInput) map[string]string {
+	errs := map[string]string{}
+
+	in.SourceTitle = strings.TrimSpace(in.SourceTitle)
+
+	if !in.SourceType.Valid() {
+		errs["source_type"] = "must be one of book, oral_teaching, letter, homily, sermon"
+	}
+	if in.SourceTitle == "" {
+		errs["source_title"] = "required"
+	}
+	if in.URL != nil {
+		if u, err := url.Parse(*in.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs["url"] = "must be an absolute http(s) URL"
+		}
+	}
+	return errs
+}
+
+// validateQuote trims in, de-duplicates its topics and returns per-field errors.
+func validateQuote(in *models.QuoteInput) map[string]string {
+	errs := map[string]string{}
+
+	in.Text = strings.TrimSpace(in.Text)
+	in.Language = strings.TrimSpace(in.Language)
+	in.License = strings.TrimSpace(in.License)
+
+	if in.AuthorID <= 0 {
+		errs["author_id"] = "required"
+	}
+	if in.Text == "" {
+		errs["text"] = "required"
+	}
+	if !languagePattern.MatchString(in.Language) {
+		errs["language"] = "must be a lowercase ISO 639 code, e.g. en, el, la"
+	}
+	if in.License == "" {
+		errs["license"] = "required"
+	}
+
+	seen := map[string]bool{}
+	topics := in.Topics[:0]
+	for _, t := range in.Topics {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		topics = append(topics, t)
+	}
+	in.Topics = topics
+
+	return errs
+}
+
+// writeQuoteWriteError maps errors from quote writes to responses and
+// reports whether one was written.
+func writeQuoteWriteError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, db.ErrAuthorNotFound):
+		writeValidationErrors(w, map[string]string{"author_id": "author does not exist"})
+	case errors.Is(err, db.ErrUnknownTopic):
+		writeValidationErrors(w, map[string]string{"topics": err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	}
+	return true
+}
+
+// redactQuote trims a restricted quote (see Quote.Restricted) to its first
+// restrictedQuoteWords words and drops the original-language text. Admin
+// callers see quotes in full.
+func redactQuote(ctx context.Context, q *models.Quote) {
+	if !q.Restricted || TierFromContext(ctx) == models.TierAdmin {
+		return
+	}
+	if words := strings.Fields(q.Text); len(words) > restrictedQuoteWords {
+		q.Text = strings.Join(words[:restrictedQuoteWords], " ") + " …"
+	}
+	q.TextOriginal = nil
+	q.Headline = nil
+}
+
+func redactQuotes(ctx context.Context, quotes []models.Quote) {
+	for i := range quotes {
+		redactQuote(ctx, &quotes[i])
+	}
+}
+
+// callerName identifies the authenticated caller in audit columns such as
+// quotes.verified_by.
+func callerName(ctx context.Context) string {
+	if k := APIKeyFromContext(ctx); k != nil {
+		return k.Name
+	}
+	return ""
+}
@@ -0,0 +1,353 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/martyria/martyria/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// rateLimitWindow is the period api_keys.rate_limit is expressed in.
+const rateLimitWindow = time.Hour
+
+// RateLimitResult is the outcome of a single Limiter.Allow call.
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Time // end of the current window, or when a rejected caller may retry
+}
+
+// Limiter counts requests per key using a sliding window: the previous
+// fixed window's count is weighted by how much of it still overlaps the
+// sliding window, which smooths bursts at window boundaries.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error)
+	Close() error
+}
+
+// NewLimiter returns a Redis-backed limiter that falls back to an
+// in-process one while Redis is unreachable, including at startup, or the
+// in-process limiter alone when redisURL is unset or invalid.
+func NewLimiter(redisURL string) Limiter {
+	mem := NewMemoryLimiter()
+	if redisURL == "" {
+		log.Printf("Rate limiting: in-memory (REDIS_URL not set)")
+		return mem
+	}
+
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		log.Printf("Rate limiting: in-memory (invalid REDIS_URL: %v)", err)
+		return mem
+	}
+	client := redis.NewClient(opts)
+
+	l := &fallbackLimiter{primary: &RedisLimiter{Client: client}, fallback: mem}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Rate limiting: in-memory until Redis at %s is reachable (%v)", opts.Addr, err)
+		l.failedAt, l.lastLogAt = time.Now(), time.Now()
+		return l
+	}
+
+	log.Printf("Rate limiting: Redis at %s", opts.Addr)
+	return l
+}
+
+// RateLimitMiddleware enforces api_keys.rate_limit per hour for
+// authenticated callers and anonLimit per hour per client IP otherwise.
+// Unlimited- and admin-tier keys are not counted. Must run after APIKeyAuth.Middleware.
+func RateLimitMiddleware(limiter Limiter, anonLimit int, trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var key string
+			var limit int
+			if k := APIKeyFromContext(r.Context()); k != nil {
+				if k.Tier == models.TierUnlimited || k.Tier == models.TierAdmin {
+					next.ServeHTTP(w, r)
+					return
+				}
+				key, limit = "key:"+strconv.FormatInt(k.ID, 10), k.RateLimit
+			} else {
+				key, limit = "ip:"+clientIP(r, trustProxy), anonLimit
+			}
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := limiter.Allow(r.Context(), key, limit, rateLimitWindow)
+			if err != nil {
+				// Fail open: an unavailable limiter shouldn't take the API down.
+				log.Printf("Rate limit check: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setRateLimitHeaders(w, res)
+			if !res.Allowed {
+				writeRateLimited(w, res)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func setRateLimitHeaders(w http.ResponseWriter, res RateLimitResult) {
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(res.Reset.Unix(), 10))
+}
+
+// writeRateLimited writes a 429 with Retry-After for a rejected request.
+func writeRateLimited(w http.ResponseWriter, res RateLimitResult) {
+	retryAfter := int(math.Ceil(time.Until(res.Reset).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	writeJSON(w, http.StatusTooManyRequests, models.ErrorResponse{
+		Error:   "rate limit exceeded",
+		Message: fmt.Sprintf("limit is %d requests per hour", res.Limit),
+	})
+}
+
+// --- Redis ---
+
+// RedisLimiter keeps one counter per key and fixed window in Redis.
+type RedisLimiter struct {
+	Client *redis.Client
+}
+
+// slidingWindowScript checks the weighted estimate before incrementing, so
+// rejected requests don't count against the caller.
+//
+// KEYS[1] = current window counter, KEYS[2] = previous window counter
+// ARGV[1] = weight of previous window, ARGV[2] = limit, ARGV[3] = TTL ms
+var slidingWindowScript = redis.NewScript(`
+local curr = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+local estimate = math.floor(prev * tonumber(ARGV[1])) + curr
+if estimate >= tonumber(ARGV[2]) then
+	return {0, estimate, prev, curr}
+end
+curr = redis.call('INCR', KEYS[1])
+if curr == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return {1, estimate + 1, prev, curr}
+`)
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	now := time.Now()
+	start, weight := windowPosition(now, window)
+	currKey := fmt.Sprintf("ratelimit:%s:%d", key, start.Unix())
+	prevKey := fmt.Sprintf("ratelimit:%s:%d", key, start.Add(-window).Unix())
+
+	vals, err := slidingWindowScript.Run(ctx, l.Client,
+		[]string{currKey, prevKey},
+		weight, limit, (2 * window).Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("redis rate limit: %w", err)
+	}
+
+	return newRateLimitResult(vals[0] == 1, int(vals[1]), limit,
+		resetAt(vals[0] == 1, start, window, int(vals[2]), int(vals[3]), limit)), nil
+}
+
+func (l *RedisLimiter) Close() error {
+	return l.Client.Close()
+}
+
+// primaryRetryInterval is how long fallbackLimiter uses only its fallback
+// after primary fails, so that an unreachable Redis doesn't add a
+// connection timeout to every request.
+const primaryRetryInterval = 30 * time.Second
+
+// fallbackLimiter uses primary and switches to fallback while primary
+// fails, e.g. while Redis is restarting, trying primary again every
+// primaryRetryInterval.
+type fallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+
+	mu        sync.Mutex
+	failedAt  time.Time
+	lastLogAt time.Time
+}
+
+func (l *fallbackLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	l.mu.Lock()
+	retry := time.Since(l.failedAt) >= primaryRetryInterval
+	l.mu.Unlock()
+
+	if retry {
+		res, err := l.primary.Allow(ctx, key, limit, window)
+		if err == nil {
+			return res, nil
+		}
+
+		l.mu.Lock()
+		l.failedAt = time.Now()
+		if time.Since(l.lastLogAt) > time.Minute {
+			log.Printf("Rate limiting falling back to in-memory: %v", err)
+			l.lastLogAt = time.Now()
+		}
+		l.mu.Unlock()
+	}
+
+	return l.fallback.Allow(ctx, key, limit, window)
+}
+
+func (l *fallbackLimiter) Close() error {
+	l.fallback.Close()
+	return l.primary.Close()
+}
+
+// --- In-memory ---
+
+// MemoryLimiter is a per-process sliding window limiter. Limits are per
+// replica, so it is only exact for single-instance deployments.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*memWindow
+	lastSweep time.Time
+}
+
+type memWindow struct {
+	start time.Time
+	curr  int
+	prev  int
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{windows: map[string]*memWindow{}, lastSweep: time.Now()}
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	now := time.Now()
+	start, weight := windowPosition(now, window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now, window)
+
+	w, ok := l.windows[key]
+	switch {
+	case !ok:
+		w = &memWindow{start: start}
+		l.windows[key] = w
+	case w.start.Equal(start.Add(-window)):
+		w.start, w.prev, w.curr = start, w.curr, 0
+	case !w.start.Equal(start):
+		w.start, w.prev, w.curr = start, 0, 0
+	}
+
+	estimate := int(float64(w.prev)*weight) + w.curr
+	if estimate >= limit {
+		return newRateLimitResult(false, estimate, limit, resetAt(false, start, window, w.prev, w.curr, limit)), nil
+	}
+	w.curr++
+	return newRateLimitResult(true, estimate+1, limit, start.Add(window)), nil
+}
+
+func (l *MemoryLimiter) Close() error {
+	return nil
+}
+
+// sweep drops keys idle for two full windows. Called with l.mu held.
+func (l *MemoryLimiter) sweep(now time.Time, window time.Duration) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, w := range l.windows {
+		if now.Sub(w.start) > 2*window {
+			delete(l.windows, key)
+		}
+	}
+}
+
+// --- Helpers ---
+
+// windowPosition returns the start of the fixed window containing now and
+// the weight of the previous window in the sliding estimate.
+func windowPosition(now time.Time, window time.Duration) (time.Time, float64) {
+	start := now.Truncate(window)
+	elapsed := now.Sub(start)
+	return start, 1 - float64(elapsed)/float64(window)
+}
+
+// resetAt returns the end of the window starting at start for allowed
+// requests. For rejected ones it returns when the sliding estimate, with
+// prev and curr requests in the previous and current windows, first drops
+// below limit: later in this window as the previous one's weight fades,
+// or, once curr alone reaches limit, part-way into the next window.
+func resetAt(allowed bool, start time.Time, window time.Duration, prev, curr, limit int) time.Time {
+	end := start.Add(window)
+	if allowed {
+		return end
+	}
+
+	// floor(n*weight) + c < limit holds once n*weight < limit-c, where
+	// weight falls from 1 to 0 across the window.
+	var at time.Time
+	switch {
+	case curr < limit && prev > 0:
+		at = start.Add(time.Duration((1 - float64(limit-curr)/float64(prev)) * float64(window)))
+	case curr >= limit:
+		at = end.Add(time.Duration((1 - float64(limit)/float64(curr)) * float64(window)))
+	default:
+		at = end
+	}
+	// The estimate must be strictly below limit, and headers carry whole
+	// seconds: round up to the next second.
+	return at.Round(time.Millisecond).Truncate(time.Second).Add(time.Second)
+}
+
+func newRateLimitResult(allowed bool, used, limit int, reset time.Time) RateLimitResult {
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return RateLimitResult{Allowed: allowed, Limit: limit, Remaining: remaining, Reset: reset}
+}
+
+// clientIP returns the caller's address. X-Forwarded-For is only honoured
+// behind a trusted proxy, and then only its rightmost entry, the one that
+// proxy appended: earlier entries come from the client and can be forged.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		fwd := r.Header.Values("X-Forwarded-For")
+		if len(fwd) > 0 {
+			last := fwd[len(fwd)-1]
+			if i := strings.LastIndex(last, ","); i >= 0 {
+				last = last[i+1:]
+			}
+			if ip := strings.TrimSpace(last); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
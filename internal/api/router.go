@@ -1,7 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 	"strings"
@@ -19,20 +22,51 @@ func NewRouter(h *Handler) http.Handler {
 	mux.HandleFunc("GET /v1/authors", h.ListAuthors)
 	mux.HandleFunc("GET /v1/authors/{slug}", h.GetAuthor)
 	mux.HandleFunc("GET /v1/authors/{slug}/quotes", h.GetAuthorQuotes)
+	mux.HandleFunc("POST /v1/authors", RequireAdmin(h.CreateAuthor))
+	mux.HandleFunc("PATCH /v1/authors/{slug}", RequireAdmin(h.UpdateAuthor))
+	mux.HandleFunc("DELETE /v1/authors/{slug}", RequireAdmin(h.DeleteAuthor))
+	mux.HandleFunc("GET /v1/authors/{slug}/permissions", RequireAdmin(h.ListPermissions))
+	mux.HandleFunc("POST /v1/authors/{slug}/permissions", RequireAdmin(h.CreatePermission))
+	mux.HandleFunc("PATCH /v1/permissions/{id}", RequireAdmin(h.UpdatePermission))
+	mux.HandleFunc("DELETE /v1/permissions/{id}", RequireAdmin(h.DeletePermission))
 	mux.HandleFunc("GET /v1/quotes", h.ListQuotes)
 	mux.HandleFunc("GET /v1/quotes/random", h.RandomQuote)
 	mux.HandleFunc("GET /v1/quotes/daily", h.DailyQuote)
+	mux.HandleFunc("GET /v1/quotes/daily/archive", h.DailyArchive)
+	mux.HandleFunc("PUT /v1/quotes/daily/schedule", RequireAdmin(h.ScheduleDailyQuote))
+	mux.HandleFunc("DELETE /v1/quotes/daily/schedule", RequireAdmin(h.ClearDailySchedule))
+	mux.HandleFunc("POST /v1/quotes/daily/schedule/import", RequireAdmin(h.ImportDailySchedule))
+	mux.HandleFunc("POST /v1/quotes/daily/schedule/plan", RequireAdmin(h.PlanDailySchedule))
 	mux.HandleFunc("GET /v1/quotes/{id}", h.GetQuote)
+	mux.HandleFunc("POST /v1/quotes", RequireAdmin(h.CreateQuote))
+	mux.HandleFunc("PATCH /v1/quotes/{id}", RequireAdmin(h.UpdateQuote))
+	mux.HandleFunc("DELETE /v1/quotes/{id}", RequireAdmin(h.DeleteQuote))
+	mux.HandleFunc("POST /v1/quotes/{id}/sources", RequireAdmin(h.AddQuoteSources))
+	mux.HandleFunc("DELETE /v1/quotes/{id}/sources/{source_id}", RequireAdmin(h.DeleteQuoteSource))
+	mux.HandleFunc("POST /v1/quotes/{id}/verify", RequireAdmin(h.VerifyQuote))
+	mux.HandleFunc("POST /v1/quotes/{id}/unverify", RequireAdmin(h.UnverifyQuote))
+	mux.HandleFunc("GET /v1/review/queue", RequireAdmin(h.ReviewQueue))
 	mux.HandleFunc("GET /v1/topics", h.ListTopics)
 	mux.HandleFunc("GET /v1/topics/{slug}/quotes", h.GetTopicQuotes)
+	mux.HandleFunc("GET /v1/calendar.ics", h.CalendarFeed)
+	mux.HandleFunc("GET /v1/calendar/{date}", h.CalendarDay)
 
 	// Images
 	mux.HandleFunc("GET /v1/authors/{slug}/images", h.GetAuthorImages)
-	mux.HandleFunc("POST /v1/images/fetch", h.FetchAllImages)
-	mux.HandleFunc("POST /v1/images/fetch/{slug}", h.FetchAuthorImages)
+	if h.Config.StorageBackend == "" || h.Config.StorageBackend == "local" {
+		mux.HandleFunc("GET /data/images/{path...}", h.ServeImage)
+	}
+	mux.HandleFunc("POST /v1/images/fetch", RequireAdmin(h.FetchAllImages))
+	mux.HandleFunc("POST /v1/images/fetch/{slug}", RequireAdmin(h.FetchAuthorImages))
+
+	// Jobs
+	mux.HandleFunc("GET /v1/jobs/{id}", RequireAdmin(h.GetJob))
+	mux.HandleFunc("POST /v1/jobs/{id}/cancel", RequireAdmin(h.CancelJob))
 
 	// Wrap with middleware chain
 	var handler http.Handler = mux
+	handler = RateLimitMiddleware(h.Limiter, h.Config.RateLimitAnonymous, h.Config.TrustProxy)(handler)
+	handler = h.Auth.Middleware(handler)
 	handler = CORSMiddleware(handler)
 	handler = LoggingMiddleware(handler)
 	handler = RecoveryMiddleware(handler)
@@ -71,7 +105,7 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 func CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 		w.Header().Set("Access-Control-Max-Age", "86400")
 
@@ -83,16 +117,6 @@ func CORSMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// RateLimitMiddleware (placeholder for Redis-backed limiter later).
-func RateLimitMiddleware(requestsPerMin int) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// TODO: Implement Redis-backed rate limiting
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
 // --- Helpers ---
 
 type statusWriter struct {
@@ -113,6 +137,33 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
+// maxBodyBytes caps JSON request bodies.
+const maxBodyBytes = 1 << 20
+
+// decodeJSON decodes a single JSON value from the request body into dst,
+// rejecting unknown fields and trailing data.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("request body is empty")
+		}
+		return err
+	}
+	if dec.More() {
+		return errors.New("request body must contain a single JSON value")
+	}
+	return nil
+}
+
+// strictUnmarshal is json.Unmarshal with unknown fields rejected.
+func strictUnmarshal(data []byte, dst interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(dst)
+}
+
 func queryParam(r *http.Request, key, defaultVal string) string {
 	v := r.URL.Query().Get(key)
 	v = strings.TrimSpace(v)
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/martyria/martyria/internal/db"
+	"github.com/martyria/martyria/internal/models"
+	"github.com/martyria/martyria/internal/planner"
+)
+
+const dateLayout = "2006-01-02"
+
+// maxScheduleDays bounds date ranges accepted by the schedule endpoints.
+const maxScheduleDays = 366
+
+// archiveDefaultDays is how far either side of today the archive reaches
+// when from/to are omitted.
+const archiveDefaultDays = 30
+
+type scheduleRequest struct {
+	Tradition models.DailyTradition `json:"tradition"`
+	Date      string                `json:"date"`
+	From      string                `json:"from"`
+	To        string                `json:"to"`
+	QuoteID   int64                 `json:"quote_id"`
+	Reason    *string               `json:"reason"`
+}
+
+// ScheduleDailyQuote handles PUT /v1/quotes/daily/schedule. It assigns one
+// quote to a single "date" or to every day "from".."to" of the "tradition"
+// schedule (default "all"), replacing (i.e. rescheduling) whatever those
+// dates had.
+func (h *Handler) ScheduleDailyQuote(w http.ResponseWriter, r *http.Request) {
+	var req scheduleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+
+	from, to, errs := parseDateRange(req.Date, req.From, req.To)
+	if req.Tradition == "" {
+		req.Tradition = models.DailyAll
+	} else if !req.Tradition.Valid() {
+		errs["tradition"] = traditionError
+	}
+	if req.QuoteID <= 0 {
+		errs["quote_id"] = "required"
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	n, err := h.DB.ScheduleDailyQuote(r.Context(), req.Tradition, from, to, req.QuoteID, trimmedOrNil(req.Reason))
+	if errors.Is(err, db.ErrQuoteNotFound) {
+		writeValidationErrors(w, map[string]string{"quote_id": "quote does not exist"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tradition": req.Tradition,
+		"from":      from.Format(dateLayout),
+		"to":        to.Format(dateLayout),
+		"quote_id":  req.QuoteID,
+		"scheduled": n,
+	})
+}
+
+// ClearDailySchedule handles DELETE /v1/quotes/daily/schedule?date= (or
+// ?from=&to=), with an optional &tradition=. Cleared dates fall back to
+// automatic selection.
+func (h *Handler) ClearDailySchedule(w http.ResponseWriter, r *http.Request) {
+	from, to, errs := parseDateRange(queryParam(r, "date", ""), queryParam(r, "from", ""), queryParam(r, "to", ""))
+	tradition, ok := dailyTradition(r)
+	if !ok {
+		errs["tradition"] = traditionError
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	n, err := h.DB.ClearDailyQuotes(r.Context(), tradition, from, to)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tradition": tradition,
+		"from":      from.Format(dateLayout),
+		"to":        to.Format(dateLayout),
+		"cleared":   n,
+	})
+}
+
+// ImportDailySchedule handles POST /v1/quotes/daily/schedule/import. The
+// body is a JSON array of {date, quote_id, reason} or, with Content-Type
+// text/csv, rows of date,quote_id[,reason] (a header row is optional). The
+// import is all-or-nothing and replaces existing entries for its dates in
+// the ?tradition= schedule (default "all").
+func (h *Handler) ImportDailySchedule(w http.ResponseWriter, r *http.Request) {
+	tradition, ok := dailyTradition(r)
+	if !ok {
+		writeValidationErrors(w, map[string]string{"tradition": traditionError})
+		return
+	}
+
+	var entries []models.DailyScheduleEntry
+	var err error
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "text/csv" {
+		entries, err = parseScheduleCSV(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	} else {
+		err = decodeJSON(w, r, &entries)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+		return
+	}
+	if len(entries) == 0 {
+		writeValidationErrors(w, map[string]string{"entries": "at least one entry is required"})
+		return
+	}
+
+	errs := map[string]string{}
+	seen := map[string]int{}
+	for i := range entries {
+		e := &entries[i]
+		key := fmt.Sprintf("%d", i)
+		e.Reason = trimmedOrNil(e.Reason)
+		if _, err := time.Parse(dateLayout, e.Date); err != nil {
+			errs[key+".date"] = "must be a date in YYYY-MM-DD format"
+		} else if prev, dup := seen[e.Date]; dup {
+			errs[key+".date"] = fmt.Sprintf("duplicate of entry %d", prev)
+		} else {
+			seen[e.Date] = i
+		}
+		if e.QuoteID <= 0 {
+			errs[key+".quote_id"] = "required"
+		}
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	err = h.DB.ImportDailySchedule(r.Context(), tradition, entries)
+	if errors.Is(err, db.ErrQuoteNotFound) {
+		writeValidationErrors(w, map[string]string{"quote_id": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tradition": tradition, "imported": len(entries)})
+}
+
+type planRequest struct {
+	Tradition    models.DailyTradition `json:"tradition"`
+	From         string                `json:"from"`
+	Days         *int                  `json:"days"`
+	QuoteWindow  *int                  `json:"quote_window"`
+	AuthorWindow *int                  `json:"author_window"`
+	DryRun       bool                  `json:"dry_run"`
+}
+
+// PlanDailySchedule handles POST /v1/quotes/daily/schedule/plan. It fills
+// unscheduled days from "from" (default today) for "days" (default
+// PLANNER_HORIZON_DAYS) in the "tradition" schedule (default "all"),
+// favouring saints whose feast falls on each day in that tradition's
+// calendar. Existing entries are kept. With dry_run the plan is returned
+// unsaved.
+func (h *Handler) PlanDailySchedule(w http.ResponseWriter, r *http.Request) {
+	var req planRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid request body", Message: err.Error()})
+			return
+		}
+	}
+
+	opts := planner.Options{
+		From:         time.Now().UTC(),
+		Days:         h.Config.PlannerHorizon,
+		QuoteWindow:  h.Config.PlannerQuoteWindow,
+		AuthorWindow: h.Config.PlannerAuthorWindow,
+		Tradition:    models.DailyAll,
+		DryRun:       req.DryRun,
+	}
+	errs := map[string]string{}
+	if req.Tradition != "" {
+		if !req.Tradition.Valid() {
+			errs["tradition"] = traditionError
+		}
+		opts.Tradition = req.Tradition
+	}
+	if req.From != "" {
+		from, err := time.Parse(dateLayout, req.From)
+		if err != nil {
+			errs["from"] = "must be a date in YYYY-MM-DD format"
+		}
+		opts.From = from
+	}
+	if req.Days != nil {
+		opts.Days = *req.Days
+	}
+	if opts.Days < 1 || opts.Days > maxScheduleDays {
+		errs["days"] = fmt.Sprintf("must be between 1 and %d", maxScheduleDays)
+	}
+	if req.QuoteWindow != nil {
+		opts.QuoteWindow = *req.QuoteWindow
+	}
+	if req.AuthorWindow != nil {
+		opts.AuthorWindow = *req.AuthorWindow
+	}
+	if opts.QuoteWindow < 0 || opts.QuoteWindow > maxScheduleDays {
+		errs["quote_window"] = fmt.Sprintf("must be between 0 and %d", maxScheduleDays)
+	}
+	if opts.AuthorWindow < 0 || opts.AuthorWindow > maxScheduleDays {
+		errs["author_window"] = fmt.Sprintf("must be between 0 and %d", maxScheduleDays)
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	res, err := h.Planner.Plan(r.Context(), opts)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// DailyArchive handles GET /v1/quotes/daily/archive?from=&to=&tradition=,
+// listing scheduled quotes (past and upcoming) in date order. Defaults to
+// archiveDefaultDays either side of today in the shared schedule.
+func (h *Handler) DailyArchive(w http.ResponseWriter, r *http.Request) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	fromStr := queryParam(r, "from", today.AddDate(0, 0, -archiveDefaultDays).Format(dateLayout))
+	toStr := queryParam(r, "to", today.AddDate(0, 0, archiveDefaultDays).Format(dateLayout))
+
+	from, to, errs := parseDateRange("", fromStr, toStr)
+	tradition, ok := dailyTradition(r)
+	if !ok {
+		errs["tradition"] = traditionError
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+
+	days, err := h.DB.ListDailySchedule(r.Context(), tradition, from, to)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	for i := range days {
+		redactQuote(r.Context(), &days[i].Quote)
+	}
+
+	writeJSON(w, http.StatusOK, days)
+}
+
+// traditionError is the validation message for an unknown schedule.
+const traditionError = "must be one of all, orthodox, catholic, protestant, anglican"
+
+// dailyTradition reads the schedule from ?tradition= (or its alias
+// ?calendar=), defaulting to the shared one. ok is false for unknown values.
+func dailyTradition(r *http.Request) (models.DailyTradition, bool) {
+	t := models.DailyTradition(queryParam(r, "tradition", queryParam(r, "calendar", string(models.DailyAll))))
+	return t, t.Valid()
+}
+
+// parseDateRange accepts either a single date or an inclusive from..to
+// range of at most maxScheduleDays days.
+func parseDateRange(date, from, to string) (time.Time, time.Time, map[string]string) {
+	errs := map[string]string{}
+	if date != "" {
+		if from != "" || to != "" {
+			errs["date"] = "use either date or from/to, not both"
+			return time.Time{}, time.Time{}, errs
+		}
+		from, to = date, date
+	}
+
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		errs["from"] = "must be a date in YYYY-MM-DD format"
+	}
+	end, err := time.Parse(dateLayout, to)
+	if err != nil {
+		errs["to"] = "must be a date in YYYY-MM-DD format"
+	}
+	if len(errs) > 0 {
+		if date != "" {
+			return start, end, map[string]string{"date": "must be a date in YYYY-MM-DD format"}
+		}
+		return start, end, errs
+	}
+
+	if end.Before(start) {
+		errs["to"] = "must not be before from"
+	} else if days := int(end.Sub(start).Hours()/24) + 1; days > maxScheduleDays {
+		errs["to"] = fmt.Sprintf("range must not exceed %d days", maxScheduleDays)
+	}
+	return start, end, errs
+}
+
+// parseScheduleCSV reads date,quote_id[,reason] rows.
+func parseScheduleCSV(r io.Reader) ([]models.DailyScheduleEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var entries []models.DailyScheduleEntry
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(rec[0]), "date") {
+			continue
+		}
+		if len(rec) < 2 || len(rec) > 3 {
+			return nil, fmt.Errorf("line %d: expected date,quote_id[,reason]", line)
+		}
+
+		id, err := strconv.ParseInt(strings.TrimSpace(rec[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid quote_id %q", line, rec[1])
+		}
+		e := models.DailyScheduleEntry{Date: strings.TrimSpace(rec[0]), QuoteID: id}
+		if len(rec) == 3 {
+			e.Reason = &rec[2]
+		}
+		entries = append(entries, e)
+	}
+}
+
+// trimmedOrNil trims s and returns nil if nothing is left.
+func trimmedOrNil(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	t := strings.TrimSpace(*s)
+	if t == "" {
+		return nil
+	}
+	return &t
+}
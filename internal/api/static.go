@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/martyria/martyria/internal/models"
+)
+
+// imageCacheControl applies to served images. Downloads never overwrite an
+// existing file, so a given path always has the same content.
+const imageCacheControl = "public, max-age=31536000, immutable"
+
+// ServeImage serves a stored image from ImageDir. Lookups go through an
+// os.Root, so ".." segments and symlinks cannot escape the directory.
+// http.ServeContent handles Range, If-None-Match and If-Modified-Since.
+func (h *Handler) ServeImage(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("path")
+	if !fs.ValidPath(name) || strings.Contains(name, `\`) {
+		writeJSON(w, http.StatusBadRequest, models.ErrorResponse{Error: "invalid image path"})
+		return
+	}
+
+	root, err := os.OpenRoot(h.Config.ImageDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "image not found"})
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, models.ErrorResponse{Error: "image storage unavailable"})
+		return
+	}
+	defer root.Close()
+
+	f, err := root.Open(name)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "image not found"})
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		writeJSON(w, http.StatusNotFound, models.ErrorResponse{Error: "image not found"})
+		return
+	}
+
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.Header().Set("Cache-Control", imageCacheControl)
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
@@ -0,0 +1,260 @@
+// Package calendar interprets the feast-day strings stored on authors:
+// fixed and Pascha-relative feasts, Julian and Gregorian dates, and the
+// Orthodox and Western Pascha.
+package calendar
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar is the calendar a fixed feast's month and day are reckoned in.
+// Movable feasts follow that calendar's Pascha: Orthodox for Julian,
+// Western for Gregorian.
+type Calendar string
+
+const (
+	Julian    Calendar = "julian"
+	Gregorian Calendar = "gregorian"
+)
+
+func (c Calendar) Valid() bool {
+	return c == Julian || c == Gregorian
+}
+
+// Pascha returns the civil date of Pascha in year as reckoned with c.
+func (c Calendar) Pascha(year int) time.Time {
+	if c == Julian {
+		return OrthodoxPascha(year)
+	}
+	return WesternPascha(year)
+}
+
+// Feast is an annual commemoration: fixed on Month and Day of Calendar, or
+// movable, PaschaOffset days after (negative: before) Calendar's Pascha.
+type Feast struct {
+	Calendar     Calendar
+	Month        time.Month // zero for movable feasts
+	Day          int
+	Movable      bool
+	PaschaOffset int
+}
+
+// Dates returns the civil (proleptic Gregorian) dates on which the feast
+// falls in year, in order. A Julian fixed feast late in December can fall
+// in January of the next civil year, so a year may have none or two. A
+// February 29 feast is kept on the 28th in common years.
+func (f Feast) Dates(year int) []time.Time {
+	if f.Movable {
+		return []time.Time{f.Calendar.Pascha(year).AddDate(0, 0, f.PaschaOffset)}
+	}
+	if f.Calendar != Julian {
+		return []time.Time{time.Date(year, f.Month, f.dayIn(year), 0, 0, 0, 0, time.UTC)}
+	}
+
+	var dates []time.Time
+	for _, y := range []int{year - 1, year} {
+		if d := JulianToGregorian(y, f.Month, f.dayIn(y)); d.Year() == year {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// Matches reports whether t's civil date falls on the feast.
+func (f Feast) Matches(t time.Time) bool {
+	for _, d := range f.Dates(t.Year()) {
+		if d.Month() == t.Month() && d.Day() == t.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// FixedOn returns, for each calendar, the fixed feasts kept on t's civil
+// date: its own month and day, plus February 29 on the 28th of a common
+// year. Together with PaschaOffset it lets callers look feasts up by date.
+func FixedOn(t time.Time) []Feast {
+	jy, jm, jd := GregorianToJulian(t)
+	feasts := []Feast{
+		{Calendar: Gregorian, Month: t.Month(), Day: t.Day()},
+		{Calendar: Julian, Month: jm, Day: jd},
+	}
+	for _, f := range feasts[:2] {
+		year := t.Year()
+		if f.Calendar == Julian {
+			year = jy
+		}
+		leap := Feast{Calendar: f.Calendar, Month: time.February, Day: 29}
+		if f.Month == time.February && f.Day == 28 && leap.dayIn(year) == 28 {
+			feasts = append(feasts, leap)
+		}
+	}
+	return feasts
+}
+
+// PaschaOffset returns the number of days from c's Pascha in t's year to
+// t's civil date.
+func PaschaOffset(t time.Time, c Calendar) int {
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(d.Sub(c.Pascha(t.Year())).Hours() / 24)
+}
+
+// dayIn returns f.Day, moving February 29 to the 28th when year is not a
+// leap year in f's calendar.
+func (f Feast) dayIn(year int) int {
+	if f.Month != time.February || f.Day != 29 {
+		return f.Day
+	}
+	leap := year%4 == 0
+	if f.Calendar == Gregorian {
+		leap = leap && (year%100 != 0 || year%400 == 0)
+	}
+	if leap {
+		return 29
+	}
+	return 28
+}
+
+// ParseFeasts parses feast strings such as "June 1", "November 13 /
+// November 26", "January 2 / January 15; July 19 / August 1" or "Second
+// Sunday of Great Lent", reckoning them in cal. Multiple commemorations
+// are separated by semicolons; in "A / B" only A, the date in the church's
+// own calendar, is used. Parenthesized notes, as in "January 30 (Three
+// Hierarchs)", are ignored. Segments that cannot be read are skipped.
+func ParseFeasts(s string, cal Calendar) []Feast {
+	var feasts []Feast
+	for _, seg := range strings.Split(parenthetical.ReplaceAllString(s, " "), ";") {
+		date, _, _ := strings.Cut(seg, "/")
+		if f, ok := parseMonthDay(date); ok {
+			f.Calendar = cal
+			feasts = append(feasts, f)
+		} else if offset, ok := parseMovable(date); ok {
+			feasts = append(feasts, Feast{Calendar: cal, Movable: true, PaschaOffset: offset})
+		}
+	}
+	return feasts
+}
+
+var parenthetical = regexp.MustCompile(`\([^()]*\)`)
+
+// parseMonthDay reads "June 29", "29 June", "Jun 29th" and similar.
+func parseMonthDay(s string) (Feast, bool) {
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	if len(fields) != 2 {
+		return Feast{}, false
+	}
+
+	m, ok := parseMonth(fields[0])
+	dayStr := fields[1]
+	if !ok {
+		if m, ok = parseMonth(fields[1]); !ok {
+			return Feast{}, false
+		}
+		dayStr = fields[0]
+	}
+
+	dayStr = strings.TrimRight(strings.ToLower(dayStr), "stndrh")
+	day, err := strconv.Atoi(dayStr)
+	if err != nil || day < 1 || day > daysIn(m) {
+		return Feast{}, false
+	}
+	return Feast{Month: m, Day: day}, true
+}
+
+func parseMonth(s string) (time.Month, bool) {
+	s = strings.ToLower(strings.TrimSuffix(s, "."))
+	if len(s) < 3 {
+		return 0, false
+	}
+	for m := time.January; m <= time.December; m++ {
+		if strings.HasPrefix(strings.ToLower(m.String()), s) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// daysIn returns the most days month m can have (29 for February).
+func daysIn(m time.Month) int {
+	return time.Date(2000, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// --- Movable feasts ---
+
+// movableFeasts maps named days to their offset from Pascha.
+var movableFeasts = map[string]int{
+	"pascha":                        0,
+	"easter":                        0,
+	"easter sunday":                 0,
+	"clean monday":                  -48,
+	"ash wednesday":                 -46,
+	"sunday of orthodoxy":           -42,
+	"sunday of st. gregory palamas": -35,
+	"lazarus saturday":              -8,
+	"palm sunday":                   -7,
+	"holy thursday":                 -3,
+	"great thursday":                -3,
+	"maundy thursday":               -3,
+	"holy friday":                   -2,
+	"great friday":                  -2,
+	"good friday":                   -2,
+	"holy saturday":                 -1,
+	"great saturday":                -1,
+	"thomas sunday":                 7,
+	"mid-pentecost":                 24,
+	"ascension":                     39,
+	"pentecost":                     49,
+	"sunday of all saints":          56,
+	"corpus christi":                60,
+}
+
+var ordinals = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4,
+	"fifth": 5, "sixth": 6, "seventh": 7, "eighth": 8,
+}
+
+// ordinalSunday matches "Second Sunday of Great Lent", "3rd Sunday after
+// Pentecost" and the like.
+var ordinalSunday = regexp.MustCompile(`^(\w+) sunday (of|after) (great lent|lent|pascha|easter|pentecost)$`)
+
+// parseMovable returns the offset from Pascha of a named movable day.
+func parseMovable(s string) (int, bool) {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	s = strings.TrimPrefix(s, "the ")
+	if offset, ok := movableFeasts[s]; ok {
+		return offset, true
+	}
+
+	m := ordinalSunday.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	n, ok := ordinals[m[1]]
+	if !ok {
+		num := strings.TrimRight(m[1], "stndrh")
+		var err error
+		if n, err = strconv.Atoi(num); err != nil || n < 1 || num == m[1] {
+			return 0, false
+		}
+	}
+
+	switch m[3] {
+	case "great lent", "lent":
+		if m[2] == "of" && n <= 5 {
+			return -42 + 7*(n-1), true // the first Sunday of Lent is six weeks before Pascha
+		}
+	case "pascha", "easter":
+		if m[2] == "of" {
+			return 7 * (n - 1), true // Pascha itself is the first Sunday of Pascha
+		}
+		return 7 * n, true
+	case "pentecost":
+		if m[2] == "after" {
+			return 49 + 7*n, true
+		}
+	}
+	return 0, false
+}
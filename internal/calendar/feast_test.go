@@ -0,0 +1,69 @@
+package calendar
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFeasts(t *testing.T) {
+	tests := []struct {
+		in   string
+		cal  Calendar
+		want []Feast
+	}{
+		{"June 29", Gregorian, []Feast{{Calendar: Gregorian, Month: time.June, Day: 29}}},
+		{"29 June", Gregorian, []Feast{{Calendar: Gregorian, Month: time.June, Day: 29}}},
+		{"Jun. 29th", Gregorian, []Feast{{Calendar: Gregorian, Month: time.June, Day: 29}}},
+		{"November 13 / November 26", Julian, []Feast{{Calendar: Julian, Month: time.November, Day: 13}}},
+		{"January 2 / January 15; July 19 / August 1", Julian, []Feast{
+			{Calendar: Julian, Month: time.January, Day: 2},
+			{Calendar: Julian, Month: time.July, Day: 19},
+		}},
+		{"January 30 (Three Hierarchs)", Julian, []Feast{{Calendar: Julian, Month: time.January, Day: 30}}},
+		{"November 13; January 30 (Three Hierarchs) / February 12", Julian, []Feast{
+			{Calendar: Julian, Month: time.November, Day: 13},
+			{Calendar: Julian, Month: time.January, Day: 30},
+		}},
+		{"February 29", Gregorian, []Feast{{Calendar: Gregorian, Month: time.February, Day: 29}}},
+		{"Second Sunday of Great Lent", Julian, []Feast{{Calendar: Julian, Movable: true, PaschaOffset: -35}}},
+		{"3rd Sunday after Pentecost", Gregorian, []Feast{{Calendar: Gregorian, Movable: true, PaschaOffset: 70}}},
+		{"Thomas Sunday", Julian, []Feast{{Calendar: Julian, Movable: true, PaschaOffset: 7}}},
+		{"February 30", Gregorian, nil},
+		{"Sixth Sunday of Great Lent", Julian, nil},
+		{"unknown", Gregorian, nil},
+		{"", Gregorian, nil},
+	}
+	for _, tt := range tests {
+		if got := ParseFeasts(tt.in, tt.cal); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFeasts(%q, %s) = %+v, want %+v", tt.in, tt.cal, got, tt.want)
+		}
+	}
+}
+
+func TestFeastDates(t *testing.T) {
+	tests := []struct {
+		name  string
+		feast Feast
+		year  int
+		want  []string
+	}{
+		{"gregorian", Feast{Calendar: Gregorian, Month: time.June, Day: 29}, 2026, []string{"2026-06-29"}},
+		{"julian", Feast{Calendar: Julian, Month: time.November, Day: 13}, 2026, []string{"2026-11-26"}},
+		{"julian, previous year", Feast{Calendar: Julian, Month: time.December, Day: 25}, 2026, []string{"2026-01-07"}},
+		{"julian, none", Feast{Calendar: Julian, Month: time.December, Day: 18}, 2100, nil}, // 2099-12-31, then 2101-01-01
+		{"leap day, common year", Feast{Calendar: Gregorian, Month: time.February, Day: 29}, 2026, []string{"2026-02-28"}},
+		{"leap day, leap year", Feast{Calendar: Gregorian, Month: time.February, Day: 29}, 2028, []string{"2028-02-29"}},
+		{"movable, orthodox", Feast{Calendar: Julian, Movable: true, PaschaOffset: -35}, 2026, []string{"2026-03-08"}},
+		{"movable, western", Feast{Calendar: Gregorian, Movable: true, PaschaOffset: 49}, 2026, []string{"2026-05-24"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, d := range tt.feast.Dates(tt.year) {
+			got = append(got, d.Format(time.DateOnly))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Dates(%d) = %v, want %v", tt.name, tt.year, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package calendar
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteFolded(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"short", "SUMMARY:Feast of St. Basil"},
+		{"exact limit", "X:" + strings.Repeat("a", icsLineLimit-2)},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multibyte", "DESCRIPTION:" + strings.Repeat("Ἰωάννης ὁ Χρυσόστομος ", 10)},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		w := bufio.NewWriter(&b)
+		writeFolded(w, tt.in)
+		w.Flush()
+
+		out := b.String()
+		if !strings.HasSuffix(out, "\r\n") {
+			t.Errorf("%s: output does not end in CRLF", tt.name)
+		}
+		lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+		var unfolded strings.Builder
+		for i, line := range lines {
+			if len(line) > icsLineLimit {
+				t.Errorf("%s: line %d is %d octets", tt.name, i, len(line))
+			}
+			if !utf8.ValidString(line) {
+				t.Errorf("%s: line %d splits a UTF-8 sequence", tt.name, i)
+			}
+			if i > 0 {
+				if !strings.HasPrefix(line, " ") {
+					t.Errorf("%s: continuation line %d does not start with a space", tt.name, i)
+				}
+				line = line[1:]
+			}
+			unfolded.WriteString(line)
+		}
+		if unfolded.String() != tt.in {
+			t.Errorf("%s: unfolded output = %q, want %q", tt.name, unfolded.String(), tt.in)
+		}
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain", "plain"},
+		{"a, b; c", `a\, b\; c`},
+		{`back\slash`, `back\\slash`},
+		{"two\nlines\r\nthree", `two\nlines\nthree`},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.in); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	var b strings.Builder
+	err := WriteICS(&b, "Feasts", []Event{{
+		UID:        "feast-basil@example.org",
+		Date:       time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Stamp:      time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC),
+		Summary:    "Feast of St. Basil",
+		Categories: []string{"Orthodox"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:feast-basil@example.org\r\n",
+		"DTSTAMP:20250601T123000Z\r\n",
+		"DTSTART;VALUE=DATE:20260101\r\n",
+		"DTEND;VALUE=DATE:20260102\r\n",
+		"CATEGORIES:Orthodox\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output lacks %q", want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package calendar
+
+import "time"
+
+// unixEpochJDN is the Julian Day Number of 1970-01-01.
+const unixEpochJDN = 2440588
+
+// JulianToGregorian returns the civil (proleptic Gregorian) date, at
+// midnight UTC, of the Julian-calendar date year-month-day. Out-of-range
+// days roll over as with time.Date.
+func JulianToGregorian(year int, month time.Month, day int) time.Time {
+	a := (14 - int(month)) / 12
+	y := year + 4800 - a
+	m := int(month) + 12*a - 3
+	jdn := day + (153*m+2)/5 + 365*y + floorDiv(y, 4) - 32083
+	return time.Unix(int64(jdn-unixEpochJDN)*86400, 0).UTC()
+}
+
+// GregorianToJulian returns the Julian-calendar date of t's civil date.
+func GregorianToJulian(t time.Time) (year int, month time.Month, day int) {
+	c := jdn(t) + 32082
+	d := floorDiv(4*c+3, 1461)
+	e := c - 1461*d/4
+	m := (5*e + 2) / 153
+	day = e - (153*m+2)/5 + 1
+	month = time.Month(m + 3 - 12*(m/10))
+	year = d - 4800 + m/10
+	return year, month, day
+}
+
+// jdn returns the Julian Day Number of t's civil date.
+func jdn(t time.Time) int {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(midnight.Unix()/86400) + unixEpochJDN
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,33 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJulianGregorian(t *testing.T) {
+	tests := []struct {
+		year      int
+		month     time.Month
+		day       int
+		gregorian string
+	}{
+		{1582, time.October, 5, "1582-10-15"},   // the Gregorian reform
+		{1900, time.February, 29, "1900-03-13"}, // a Julian-only leap day
+		{2025, time.November, 13, "2025-11-26"},
+		{2025, time.December, 25, "2026-01-07"},
+		{2100, time.February, 28, "2100-03-13"},
+		{2100, time.February, 29, "2100-03-14"},
+	}
+	for _, tt := range tests {
+		got := JulianToGregorian(tt.year, tt.month, tt.day)
+		if s := got.Format(time.DateOnly); s != tt.gregorian {
+			t.Errorf("JulianToGregorian(%d, %s, %d) = %s, want %s", tt.year, tt.month, tt.day, s, tt.gregorian)
+			continue
+		}
+		y, m, d := GregorianToJulian(got)
+		if y != tt.year || m != tt.month || d != tt.day {
+			t.Errorf("GregorianToJulian(%s) = %d-%s-%d, want %d-%s-%d", tt.gregorian, y, m, d, tt.year, tt.month, tt.day)
+		}
+	}
+}
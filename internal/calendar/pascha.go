@@ -0,0 +1,37 @@
+package calendar
+
+import "time"
+
+// gregorianReformYear is the first year Easter was reckoned on the
+// Gregorian calendar.
+const gregorianReformYear = 1583
+
+// OrthodoxPascha returns the civil date of Pascha in year as reckoned by
+// the Orthodox Church: the Julian computus, on the Julian calendar.
+func OrthodoxPascha(year int) time.Time {
+	a, b, c := year%4, year%7, year%19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	n := d + e + 114
+	return JulianToGregorian(year, time.Month(n/31), n%31+1)
+}
+
+// WesternPascha returns the civil date of Easter in year as reckoned by
+// the Catholic and Protestant churches. Before the Gregorian reform the
+// West kept the Julian computus, so earlier years match OrthodoxPascha.
+func WesternPascha(year int) time.Time {
+	if year < gregorianReformYear {
+		return OrthodoxPascha(year)
+	}
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	n := h + l - 7*m + 114
+	return time.Date(year, time.Month(n/31), n%31+1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,31 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPascha(t *testing.T) {
+	tests := []struct {
+		year              int
+		orthodox, western string
+	}{
+		{2000, "2000-04-30", "2000-04-23"},
+		{2010, "2010-04-04", "2010-04-04"},
+		{2013, "2013-05-05", "2013-03-31"},
+		{2019, "2019-04-28", "2019-04-21"},
+		{2021, "2021-05-02", "2021-04-04"},
+		{2024, "2024-05-05", "2024-03-31"},
+		{2025, "2025-04-20", "2025-04-20"},
+		{2026, "2026-04-12", "2026-04-05"},
+		{2038, "2038-04-25", "2038-04-25"},
+	}
+	for _, tt := range tests {
+		if got := OrthodoxPascha(tt.year).Format(time.DateOnly); got != tt.orthodox {
+			t.Errorf("OrthodoxPascha(%d) = %s, want %s", tt.year, got, tt.orthodox)
+		}
+		if got := WesternPascha(tt.year).Format(time.DateOnly); got != tt.western {
+			t.Errorf("WesternPascha(%d) = %s, want %s", tt.year, got, tt.western)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +14,51 @@ type Config struct {
 	ImageDir    string
 	BaseURL     string
 	Version     string
+
+	// Image storage: "local" (ImageDir) or "s3"
+	StorageBackend  string
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3UseSSL        bool
+	S3PublicURL     string // public bucket/CDN base; empty means presigned URLs
+	S3PresignExpiry time.Duration
+
+	// Migrations & seeds. The SQL files are embedded in the binary; the
+	// directories override them for development when set.
+	MigrateOnStart bool
+	MigrationsDir  string
+	SeedsDir       string
+
+	// MigrationLockTimeout bounds how long an instance waits for another
+	// replica to finish migrating.
+	MigrationLockTimeout time.Duration
+
+	// API keys
+	AdminToken          string // static admin credential; empty disables it
+	APIKeyCacheTTL      time.Duration
+	APIKeyFlushInterval time.Duration
+
+	// Rate limiting (requests per hour). Keyed callers use api_keys.rate_limit.
+	RateLimitAnonymous int
+	TrustProxy         bool // take client IPs from the last X-Forwarded-For hop
+
+	// Daily quote planner defaults (days)
+	PlannerHorizon      int
+	PlannerQuoteWindow  int // don't repeat a quote within this many days
+	PlannerAuthorWindow int // don't repeat an author within this many days
+
+	// DailyTimezone is the IANA zone that decides "today" for the daily
+	// quote when the caller passes no tz.
+	DailyTimezone string
+
+	// HTTP server
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
 }
 
 func Load() *Config {
@@ -23,6 +69,40 @@ func Load() *Config {
 		ImageDir:    getEnv("IMAGE_DIR", "./data/images"),
 		BaseURL:     getEnv("BASE_URL", "http://localhost:8080"),
 		Version:     getEnv("VERSION", "0.1.0"),
+
+		StorageBackend:  getEnv("STORAGE_BACKEND", "local"),
+		S3Endpoint:      getEnv("S3_ENDPOINT", ""),
+		S3Region:        getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:        getEnv("S3_BUCKET", "martyria-images"),
+		S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:        getEnvBool("S3_USE_SSL", true),
+		S3PublicURL:     getEnv("S3_PUBLIC_URL", ""),
+		S3PresignExpiry: getEnvDuration("S3_PRESIGN_EXPIRY", time.Hour),
+
+		MigrateOnStart: getEnvBool("MIGRATE_ON_START", true),
+		MigrationsDir:  getEnv("MIGRATIONS_DIR", ""),
+		SeedsDir:       getEnv("SEEDS_DIR", ""),
+
+		MigrationLockTimeout: getEnvDuration("MIGRATION_LOCK_TIMEOUT", time.Minute),
+
+		AdminToken:          getEnv("ADMIN_TOKEN", ""),
+		APIKeyCacheTTL:      getEnvDuration("API_KEY_CACHE_TTL", 5*time.Minute),
+		APIKeyFlushInterval: getEnvDuration("API_KEY_FLUSH_INTERVAL", time.Minute),
+
+		RateLimitAnonymous: getEnvInt("RATE_LIMIT_ANONYMOUS", 60),
+		TrustProxy:         getEnvBool("TRUST_PROXY", false),
+
+		PlannerHorizon:      getEnvInt("PLANNER_HORIZON_DAYS", 90),
+		PlannerQuoteWindow:  getEnvInt("PLANNER_QUOTE_WINDOW_DAYS", 180),
+		PlannerAuthorWindow: getEnvInt("PLANNER_AUTHOR_WINDOW_DAYS", 7),
+
+		DailyTimezone: getEnv("DAILY_TIMEZONE", "UTC"),
+
+		ReadTimeout:     getEnvDuration("HTTP_READ_TIMEOUT", 10*time.Second),
+		WriteTimeout:    getEnvDuration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:     getEnvDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
 	}
 }
 
@@ -49,3 +129,22 @@ func getEnvInt(key string, fallback int) int {
 	}
 	return fallback
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	if val, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+// getEnvDuration accepts Go duration strings ("30s", "2m").
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if val, ok := os.LookupEnv(key); ok {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
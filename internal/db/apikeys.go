@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/martyria/martyria/internal/models"
+)
+
+// GetAPIKeyByHash looks up an API key by the SHA-256 hash of its value.
+// Returns nil if no key matches; inactive keys are returned as-is.
+func (d *DB) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	k := &models.APIKey{}
+	err := d.Pool.QueryRow(ctx, `
+		SELECT id, name, email, tier, rate_limit, active, created_at, last_used
+		FROM api_keys
+		WHERE key_hash = $1
+	`, keyHash).Scan(
+		&k.ID, &k.Name, &k.Email, &k.Tier, &k.RateLimit, &k.Active, &k.CreatedAt, &k.LastUsed,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get api key: %w", err)
+	}
+	return k, nil
+}
+
+// CreateAPIKey stores a new key by its hash. The plaintext key is never stored.
+func (d *DB) CreateAPIKey(ctx context.Context, keyHash string, k *models.APIKey) error {
+	err := d.Pool.QueryRow(ctx, `
+		INSERT INTO api_keys (key_hash, name, email, tier, rate_limit)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, active, created_at
+	`, keyHash, k.Name, k.Email, k.Tier, k.RateLimit).Scan(&k.ID, &k.Active, &k.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create api key: %w", err)
+	}
+	return nil
+}
+
+// SetAPIKeyActive activates or revokes a key. Returns false if it does not exist.
+func (d *DB) SetAPIKeyActive(ctx context.Context, id int64, active bool) (bool, error) {
+	tag, err := d.Pool.Exec(ctx, "UPDATE api_keys SET active = $2 WHERE id = $1", id, active)
+	if err != nil {
+		return false, fmt.Errorf("set api key active: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// TouchAPIKeys records last_used for many keys in one statement.
+func (d *DB) TouchAPIKeys(ctx context.Context, lastUsed map[int64]time.Time) error {
+	if len(lastUsed) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(lastUsed))
+	times := make([]time.Time, 0, len(lastUsed))
+	for id, t := range lastUsed {
+		ids = append(ids, id)
+		times = append(times, t)
+	}
+
+	_, err := d.Pool.Exec(ctx, `
+		UPDATE api_keys k SET last_used = v.last_used
+		FROM unnest($1::bigint[], $2::timestamptz[]) AS v(id, last_used)
+		WHERE k.id = v.id AND (k.last_used IS NULL OR k.last_used < v.last_used)
+	`, ids, times)
+	if err != nil {
+		return fmt.Errorf("touch api keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/martyria/martyria/internal/models"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrSlugTaken is returned when an explicitly requested slug is in use.
+	ErrSlugTaken = errors.New("slug already in use")
+
+	// ErrAuthorHasDependents is returned by DeleteAuthor when the author
+	// still has quotes or permission records and cascade was not requested.
+	ErrAuthorHasDependents = errors.New("author has quotes or permission records")
+)
+
+// slugAttempts bounds retries when a generated slug is claimed concurrently.
+const slugAttempts = 5
+
+const authorWriteColumns = `slug, name, name_original, title, born_year, died_year,
+	era, tradition, bio, bio_short, canonized, canonized_date, canonized_by,
+	feast_day_orthodox, feast_day_catholic, copyright_status,
+	wikipedia_url, wikimedia_category,
+	name_original_folded, name_original_latin`
+
+func authorWriteArgs(in models.AuthorInput) []interface{} {
+	folded, latin := searchKeys(in.NameOriginal)
+	return []interface{}{
+		in.Slug, in.Name, in.NameOriginal, in.Title, in.BornYear, in.DiedYear,
+		in.Era, in.Tradition, in.Bio, in.BioShort, in.Canonized, in.CanonizedDate, in.CanonizedBy,
+		in.FeastDayOrthodox, in.FeastDayCatholic, in.CopyrightStatus,
+		in.WikipediaURL, in.WikimediaCategory,
+		folded, latin,
+	}
+}
+
+// CreateAuthor inserts an author, together with the author_feasts rows
+// parsed from its feast strings. When in.Slug is empty one is generated
+// from the name, suffixed with -2, -3, ... on collision; an explicit slug
+// that is already taken yields ErrSlugTaken.
+func (d *DB) CreateAuthor(ctx context.Context, in models.AuthorInput) (*models.Author, error) {
+	generate := in.Slug == ""
+
+	for attempt := 0; attempt < slugAttempts; attempt++ {
+		if generate {
+			slug, err := d.freeAuthorSlug(ctx, Slugify(in.Name))
+			if err != nil {
+				return nil, err
+			}
+			in.Slug = slug
+		}
+
+		err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+			var id int64
+			err := tx.QueryRow(ctx, `
+				INSERT INTO authors (`+authorWriteColumns+`)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+				RETURNING id
+			`, authorWriteArgs(in)...).Scan(&id)
+			if err != nil {
+				return err
+			}
+			return setAuthorFeasts(ctx, tx, id, in.FeastDayOrthodox, in.FeastDayCatholic)
+		})
+		if err == nil {
+			return d.GetAuthor(ctx, in.Slug)
+		}
+		if !isUniqueViolation(err, "authors_slug_key") {
+			return nil, fmt.Errorf("create author: %w", err)
+		}
+		if !generate {
+			return nil, ErrSlugTaken
+		}
+	}
+	return nil, fmt.Errorf("create author: could not allocate a slug for %q", in.Name)
+}
+
+// UpdateAuthor replaces the writable fields of the author with the given
+// ID, re-deriving its author_feasts rows if the feast strings changed.
+// Returns nil if the author does not exist.
+func (d *DB) UpdateAuthor(ctx context.Context, id int64, in models.AuthorInput) (*models.Author, error) {
+	var found bool
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		var oldOrthodox, oldCatholic *string
+		err := tx.QueryRow(ctx,
+			"SELECT feast_day_orthodox, feast_day_catholic FROM authors WHERE id = $1 FOR UPDATE", id,
+		).Scan(&oldOrthodox, &oldCatholic)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+
+		_, err = tx.Exec(ctx, `
+			UPDATE authors SET
+				slug = $2, name = $3, name_original = $4, title = $5, born_year = $6, died_year = $7,
+				era = $8, tradition = $9, bio = $10, bio_short = $11,
+				canonized = $12, canonized_date = $13, canonized_by = $14,
+				feast_day_orthodox = $15, feast_day_catholic = $16, copyright_status = $17,
+				wikipedia_url = $18, wikimedia_category = $19,
+				name_original_folded = $20, name_original_latin = $21
+			WHERE id = $1
+		`, append([]interface{}{id}, authorWriteArgs(in)...)...)
+		if err != nil {
+			return err
+		}
+		if equalStrings(oldOrthodox, in.FeastDayOrthodox) && equalStrings(oldCatholic, in.FeastDayCatholic) {
+			return nil
+		}
+		return setAuthorFeasts(ctx, tx, id, in.FeastDayOrthodox, in.FeastDayCatholic)
+	})
+	if err != nil {
+		if isUniqueViolation(err, "authors_slug_key") {
+			return nil, ErrSlugTaken
+		}
+		return nil, fmt.Errorf("update author: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return d.GetAuthor(ctx, in.Slug)
+}
+
+// DeleteAuthor removes an author. Unless cascade is set, authors with
+// quotes or permission records are kept and ErrAuthorHasDependents is
+// returned; with cascade, those rows (and the author's images) go too.
+// Returns false if the author does not exist.
+func (d *DB) DeleteAuthor(ctx context.Context, id int64, cascade bool) (bool, error) {
+	var deleted bool
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		var hasDependents bool
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM quotes WHERE author_id = $1)
+				OR EXISTS (SELECT 1 FROM permissions WHERE author_id = $1)
+		`, id).Scan(&hasDependents)
+		if err != nil {
+			return err
+		}
+		if hasDependents {
+			if !cascade {
+				return ErrAuthorHasDependents
+			}
+			// permissions.author_id has no ON DELETE CASCADE.
+			if _, err := tx.Exec(ctx, "DELETE FROM permissions WHERE author_id = $1", id); err != nil {
+				return err
+			}
+		}
+
+		tag, err := tx.Exec(ctx, "DELETE FROM authors WHERE id = $1", id)
+		if err != nil {
+			return err
+		}
+		deleted = tag.RowsAffected() > 0
+		return nil
+	})
+	if errors.Is(err, ErrAuthorHasDependents) {
+		return false, err
+	}
+	if err != nil {
+		return false, fmt.Errorf("delete author: %w", err)
+	}
+	return deleted, nil
+}
+
+// freeAuthorSlug returns base if unused, otherwise the first free base-N.
+func (d *DB) freeAuthorSlug(ctx context.Context, base string) (string, error) {
+	if base == "" {
+		base = "author"
+	}
+
+	// Slugify output contains no LIKE wildcards.
+	rows, err := d.Pool.Query(ctx,
+		"SELECT slug FROM authors WHERE slug = $1 OR slug LIKE $1 || '-%'", base)
+	if err != nil {
+		return "", fmt.Errorf("find free slug: %w", err)
+	}
+	taken, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", fmt.Errorf("find free slug: %w", err)
+	}
+
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+	if !used[base] {
+		return base, nil
+	}
+	for n := 2; ; n++ {
+		if s := base + "-" + strconv.Itoa(n); !used[s] {
+			return s, nil
+		}
+	}
+}
+
+// Slugify lowercases s, strips diacritics and joins the remaining letters
+// and digits with hyphens: "Maximus the Confessor" → "maximus-the-confessor".
+func Slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFKD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining mark split off by NFKD.
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			// "Mary's" → "marys"
+		default:
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// equalStrings reports whether a and b are both nil or point to equal strings.
+func equalStrings(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// isUniqueViolation reports whether err is a unique violation, optionally
+// of a specific constraint.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return false
+	}
+	return constraint == "" || pgErr.ConstraintName == constraint
+}
@@ -3,17 +3,21 @@ package db
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log"
-	"os"
-	"path/filepath"
 	"sort"
-	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type DB struct {
 	Pool *pgxpool.Pool
+
+	// MigrationLockTimeout bounds how long RunMigrations and MigrateDown
+	// wait for another instance's migration lock. Zero waits indefinitely.
+	MigrationLockTimeout time.Duration
 }
 
 func New(ctx context.Context, connString string) (*DB, error) {
@@ -34,64 +38,69 @@ func New(ctx context.Context, connString string) (*DB, error) {
 		return nil, fmt.Errorf("ping db: %w", err)
 	}
 
-	return &DB{Pool: pool}, nil
+	return &DB{Pool: pool, MigrationLockTimeout: time.Minute}, nil
 }
 
 func (d *DB) Close() {
 	d.Pool.Close()
 }
 
-// RunMigrations executes all .up.sql files in order
-func (d *DB) RunMigrations(ctx context.Context, migrationsDir string) error {
-	// Create migrations tracking table
+// RunSeeds executes all .sql files at the root of seedsFS in order. Each file runs in
+// its own transaction and is recorded in schema_seeds so that re-running
+// is a no-op; force re-applies files that were already recorded. Seed files
+// must be idempotent: force re-runs them, and databases seeded before
+// schema_seeds existed have no records and get every file again.
+func (d *DB) RunSeeds(ctx context.Context, seedsFS fs.FS, force bool) ([]string, error) {
 	_, err := d.Pool.Exec(ctx, `
-		CREATE TABLE IF NOT EXISTS schema_migrations (
-			version TEXT PRIMARY KEY,
+		CREATE TABLE IF NOT EXISTS schema_seeds (
+			name TEXT PRIMARY KEY,
 			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
 		)
 	`)
 	if err != nil {
-		return fmt.Errorf("create migrations table: %w", err)
+		return nil, fmt.Errorf("create seeds table: %w", err)
 	}
 
-	// Find all .up.sql files
-	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.up.sql"))
+	files, err := fs.Glob(seedsFS, "*.sql")
 	if err != nil {
-		return fmt.Errorf("glob migrations: %w", err)
+		return nil, fmt.Errorf("glob seeds: %w", err)
 	}
 	sort.Strings(files)
 
-	for _, file := range files {
-		version := filepath.Base(file)
-		version = strings.TrimSuffix(version, ".up.sql")
-
-		// Check if already applied
-		var count int
-		err := d.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM schema_migrations WHERE version = $1", version).Scan(&count)
-		if err != nil {
-			return fmt.Errorf("check migration %s: %w", version, err)
-		}
-		if count > 0 {
-			continue
-		}
-
-		// Read and execute migration
-		sql, err := os.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("read migration %s: %w", file, err)
+	var applied []string
+	for _, name := range files {
+		if !force {
+			var count int
+			err := d.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM schema_seeds WHERE name = $1", name).Scan(&count)
+			if err != nil {
+				return applied, fmt.Errorf("check seed %s: %w", name, err)
+			}
+			if count > 0 {
+				continue
+			}
 		}
 
-		log.Printf("Applying migration: %s", version)
-		_, err = d.Pool.Exec(ctx, string(sql))
+		sql, err := fs.ReadFile(seedsFS, name)
 		if err != nil {
-			return fmt.Errorf("execute migration %s: %w", version, err)
+			return applied, fmt.Errorf("read seed %s: %w", name, err)
 		}
 
-		_, err = d.Pool.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version)
+		log.Printf("Applying seed: %s", name)
+		err = pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, string(sql)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, `
+				INSERT INTO schema_seeds (name) VALUES ($1)
+				ON CONFLICT (name) DO UPDATE SET applied_at = now()
+			`, name)
+			return err
+		})
 		if err != nil {
-			return fmt.Errorf("record migration %s: %w", version, err)
+			return applied, fmt.Errorf("apply seed %s: %w", name, err)
 		}
+		applied = append(applied, name)
 	}
 
-	return nil
+	return applied, nil
 }
@@ -0,0 +1,338 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/martyria/martyria/internal/calendar"
+	"github.com/martyria/martyria/internal/models"
+)
+
+// feastColumns pairs each feast string on authors with the tradition and
+// calendar its dates are reckoned in: Orthodox dates are Julian (old
+// calendar), Catholic ones Gregorian.
+var feastColumns = []struct {
+	tradition string
+	calendar  calendar.Calendar
+}{
+	{"orthodox", calendar.Julian},
+	{"catholic", calendar.Gregorian},
+}
+
+// parseAuthorFeasts turns an author's feast strings into author_feasts
+// rows. The first commemoration in each string is ranked the feast, later
+// ones commemorations.
+func parseAuthorFeasts(orthodox, catholic *string) []models.AuthorFeast {
+	var rows []models.AuthorFeast
+	for i, s := range []*string{orthodox, catholic} {
+		if s == nil {
+			continue
+		}
+		col := feastColumns[i]
+		for n, f := range calendar.ParseFeasts(*s, col.calendar) {
+			row := models.AuthorFeast{
+				Tradition: col.tradition,
+				Calendar:  string(f.Calendar),
+				Rank:      models.FeastRankFeast,
+			}
+			if n > 0 {
+				row.Rank = models.FeastRankCommemoration
+			}
+			if f.Movable {
+				offset := f.PaschaOffset
+				row.PaschaOffset = &offset
+			} else {
+				month, day := int(f.Month), f.Day
+				row.Month, row.Day = &month, &day
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// setAuthorFeasts replaces the author's author_feasts rows with those
+// parsed from the feast strings.
+func setAuthorFeasts(ctx context.Context, tx pgx.Tx, authorID int64, orthodox, catholic *string) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM author_feasts WHERE author_id = $1", authorID); err != nil {
+		return err
+	}
+	batch := &pgx.Batch{}
+	for _, f := range parseAuthorFeasts(orthodox, catholic) {
+		batch.Queue(`
+			INSERT INTO author_feasts (author_id, tradition, calendar, month, day, pascha_offset, rank)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, authorID, f.Tradition, f.Calendar, f.Month, f.Day, f.PaschaOffset, f.Rank)
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// BackfillAuthorFeasts derives author_feasts rows from the feast strings of
+// authors that have none, or of every author (replacing their rows) when
+// force is set. Returns the number of authors processed.
+func (d *DB) BackfillAuthorFeasts(ctx context.Context, force bool) (int, error) {
+	type pending struct {
+		id                 int64
+		orthodox, catholic *string
+	}
+	var authors []pending
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT a.id, a.feast_day_orthodox, a.feast_day_catholic
+			FROM authors a
+			WHERE $1 OR (
+				(a.feast_day_orthodox IS NOT NULL OR a.feast_day_catholic IS NOT NULL)
+				AND NOT EXISTS (SELECT 1 FROM author_feasts f WHERE f.author_id = a.id)
+			)
+			ORDER BY a.id
+			FOR UPDATE
+		`, force)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			p := pending{}
+			if err := rows.Scan(&p.id, &p.orthodox, &p.catholic); err != nil {
+				rows.Close()
+				return err
+			}
+			authors = append(authors, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, p := range authors {
+			if err := setAuthorFeasts(ctx, tx, p.id, p.orthodox, p.catholic); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("backfill author feasts: %w", err)
+	}
+	return len(authors), nil
+}
+
+func (d *DB) getAuthorFeasts(ctx context.Context, authorID int64) ([]models.AuthorFeast, error) {
+	rows, err := d.Pool.Query(ctx, `
+		SELECT tradition, calendar, month, day, pascha_offset, rank
+		FROM author_feasts
+		WHERE author_id = $1
+		ORDER BY tradition DESC, rank DESC, id -- orthodox first, principal feast first
+	`, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("get author feasts: %w", err)
+	}
+	defer rows.Close()
+
+	var feasts []models.AuthorFeast
+	for rows.Next() {
+		f := models.AuthorFeast{}
+		if err := rows.Scan(&f.Tradition, &f.Calendar, &f.Month, &f.Day, &f.PaschaOffset, &f.Rank); err != nil {
+			return nil, fmt.Errorf("scan author feast: %w", err)
+		}
+		feasts = append(feasts, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get author feasts: %w", err)
+	}
+	return feasts, nil
+}
+
+// FeastsOn returns the authors commemorated on date's civil day, in the
+// order of ListAuthors. tradition ("orthodox" or "catholic") limits the
+// feasts considered; empty means both.
+func (d *DB) FeastsOn(ctx context.Context, date time.Time, tradition string) ([]models.Commemoration, error) {
+	var cals []string
+	var months, days []int
+	for _, f := range calendar.FixedOn(date) {
+		cals = append(cals, string(f.Calendar))
+		months = append(months, int(f.Month))
+		days = append(days, f.Day)
+	}
+
+	rows, err := d.Pool.Query(ctx, `
+		SELECT f.tradition, f.rank,
+			a.id, a.slug, a.name, a.name_original, a.title,
+			a.born_year, a.died_year, a.era, a.tradition,
+			a.bio_short, a.canonized, a.copyright_status,
+			a.feast_day_orthodox, a.feast_day_catholic,
+			a.created_at, a.updated_at
+		FROM author_feasts f
+		JOIN authors a ON a.id = f.author_id
+		WHERE ($1 = '' OR f.tradition = $1)
+			AND ((f.calendar, f.month, f.day) IN (SELECT * FROM unnest($2::text[], $3::int[], $4::int[]))
+				OR (f.calendar = 'julian' AND f.pascha_offset = $5)
+				OR (f.calendar = 'gregorian' AND f.pascha_offset = $6))
+		ORDER BY a.born_year ASC NULLS LAST, a.name ASC, f.tradition DESC
+	`, tradition, cals, months, days,
+		calendar.PaschaOffset(date, calendar.Julian), calendar.PaschaOffset(date, calendar.Gregorian))
+	if err != nil {
+		return nil, fmt.Errorf("feasts on date: %w", err)
+	}
+	defer rows.Close()
+
+	comms := []models.Commemoration{}
+	for rows.Next() {
+		c := models.Commemoration{}
+		a := &c.Author
+		if err := rows.Scan(
+			&c.Tradition, &c.Rank,
+			&a.ID, &a.Slug, &a.Name, &a.NameOriginal, &a.Title,
+			&a.BornYear, &a.DiedYear, &a.Era, &a.Tradition,
+			&a.BioShort, &a.Canonized, &a.CopyrightStatus,
+			&a.FeastDayOrthodox, &a.FeastDayCatholic,
+			&a.CreatedAt, &a.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan commemoration: %w", err)
+		}
+		s := a.FeastDayOrthodox
+		if c.Tradition == "catholic" {
+			s = a.FeastDayCatholic
+		}
+		if s != nil {
+			c.Feast = *s
+		}
+		comms = append(comms, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("feasts on date: %w", err)
+	}
+	return comms, nil
+}
+
+// FeastFilter narrows ListFeasts; zero fields match everything.
+type FeastFilter struct {
+	Tradition string   // "orthodox" or "catholic"
+	Era       string   // author era
+	Slugs     []string // authors
+}
+
+// FeastEntry is an author_feasts row with its author and, when the author
+// has one, a featured quote.
+type FeastEntry struct {
+	ID        int64
+	Tradition string
+	Rank      models.FeastRank
+	Feast     calendar.Feast
+	Author    models.Author
+	Quote     *models.Quote
+}
+
+// ListFeasts returns the feasts matching f, ordered like ListAuthors. The
+// featured quote is the author's shortest verified, unrestricted quote, so
+// that it fits in a calendar entry.
+func (d *DB) ListFeasts(ctx context.Context, f FeastFilter) ([]FeastEntry, error) {
+	rows, err := d.Pool.Query(ctx, `
+		SELECT f.id, f.tradition, f.rank, f.calendar, f.month, f.day, f.pascha_offset,
+			a.id, a.slug, a.name, a.canonized, a.bio_short, a.copyright_status, a.updated_at,
+			`+permissionFromExpr+`,
+			fq.id, fq.text, fq.source_work, fq.source_publisher
+		FROM author_feasts f
+		JOIN authors a ON a.id = f.author_id
+		LEFT JOIN LATERAL (
+			SELECT q.id, q.text, q.source_work, q.source_publisher
+			FROM quotes q
+			WHERE q.author_id = a.id AND q.verified AND NOT `+quoteRestrictedExpr+`
+			ORDER BY length(q.text), q.id
+			LIMIT 1
+		) fq ON true
+		WHERE ($1 = '' OR f.tradition = $1)
+			AND ($2 = '' OR a.era::text = $2)
+			AND ($3::text[] IS NULL OR a.slug = ANY($3::text[]))
+		ORDER BY a.born_year ASC NULLS LAST, a.name ASC, f.tradition DESC, f.rank DESC, f.id
+	`, f.Tradition, f.Era, f.Slugs)
+	if err != nil {
+		return nil, fmt.Errorf("list feasts: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []FeastEntry
+	for rows.Next() {
+		e := FeastEntry{}
+		a := &e.Author
+		var cal string
+		var month, day, offset *int
+		var quoteID *int64
+		var quoteText *string
+		q := &models.Quote{}
+		if err := rows.Scan(
+			&e.ID, &e.Tradition, &e.Rank, &cal, &month, &day, &offset,
+			&a.ID, &a.Slug, &a.Name, &a.Canonized, &a.BioShort, &a.CopyrightStatus, &a.UpdatedAt,
+			&a.PermissionFrom,
+			&quoteID, &quoteText, &q.SourceWork, &q.SourcePublisher,
+		); err != nil {
+			return nil, fmt.Errorf("scan feast: %w", err)
+		}
+
+		e.Feast = feastFromRow(cal, month, day, offset)
+		if quoteID != nil {
+			q.ID, q.AuthorID, q.Text = *quoteID, a.ID, *quoteText
+			q.Attribution = buildAttribution(q, a)
+			e.Quote = q
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list feasts: %w", err)
+	}
+	return entries, nil
+}
+
+// feastFromRow builds the calendar.Feast of an author_feasts row.
+func feastFromRow(cal string, month, day, offset *int) calendar.Feast {
+	f := calendar.Feast{Calendar: calendar.Calendar(cal)}
+	if offset != nil {
+		f.Movable, f.PaschaOffset = true, *offset
+	} else {
+		f.Month, f.Day = time.Month(*month), *day
+	}
+	return f
+}
+
+// TraditionFeast is an author_feasts row as a calendar.Feast with the
+// tradition that keeps it and its rank.
+type TraditionFeast struct {
+	Tradition string
+	Rank      models.FeastRank
+	Feast     calendar.Feast
+}
+
+// feastsByAuthor returns the author_feasts rows of the given authors.
+func (d *DB) feastsByAuthor(ctx context.Context, authorIDs []int64) (map[int64][]TraditionFeast, error) {
+	rows, err := d.Pool.Query(ctx, `
+		SELECT author_id, tradition, rank, calendar, month, day, pascha_offset
+		FROM author_feasts
+		WHERE author_id = ANY($1)
+		ORDER BY author_id, tradition DESC, rank DESC, id
+	`, authorIDs)
+	if err != nil {
+		return nil, fmt.Errorf("feasts by author: %w", err)
+	}
+	defer rows.Close()
+
+	feasts := map[int64][]TraditionFeast{}
+	for rows.Next() {
+		var authorID int64
+		var tradition, cal string
+		var rank models.FeastRank
+		var month, day, offset *int
+		if err := rows.Scan(&authorID, &tradition, &rank, &cal, &month, &day, &offset); err != nil {
+			return nil, fmt.Errorf("scan author feast: %w", err)
+		}
+		feasts[authorID] = append(feasts[authorID], TraditionFeast{
+			Tradition: tradition,
+			Rank:      rank,
+			Feast:     feastFromRow(cal, month, day, offset),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("feasts by author: %w", err)
+	}
+	return feasts, nil
+}
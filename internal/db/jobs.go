@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/martyria/martyria/internal/models"
+)
+
+// ErrJobRunning is returned by CreateJob when a job of the same kind is
+// already running.
+var ErrJobRunning = errors.New("a job of this kind is already running")
+
+// jobStaleAfter is how long a running job may go without a heartbeat
+// (updated_at) before CreateJob assumes its process died and fails it.
+const jobStaleAfter = 15 * time.Minute
+
+const jobColumns = `id, kind, state, progress_done, progress_total, error,
+	cancel_requested, created_by, created_at, updated_at, finished_at`
+
+func scanJob(row pgx.Row, j *models.Job) error {
+	return row.Scan(
+		&j.ID, &j.Kind, &j.State, &j.ProgressDone, &j.ProgressTotal, &j.Error,
+		&j.CancelRequested, &j.CreatedBy, &j.CreatedAt, &j.UpdatedAt, &j.FinishedAt,
+	)
+}
+
+// CreateJob inserts a running job. If another job of the same kind is
+// running, it returns that job together with ErrJobRunning.
+func (d *DB) CreateJob(ctx context.Context, kind string, createdBy *string) (*models.Job, error) {
+	// Reap jobs whose process died without finishing them.
+	_, err := d.Pool.Exec(ctx, `
+		UPDATE jobs SET state = 'failed', error = 'no progress reported; presumed interrupted', finished_at = now()
+		WHERE kind = $1 AND state = 'running' AND updated_at < now() - make_interval(secs => $2)
+	`, kind, jobStaleAfter.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("reap stale jobs: %w", err)
+	}
+
+	j := &models.Job{}
+	for attempt := 0; ; attempt++ {
+		err = scanJob(d.Pool.QueryRow(ctx, `
+			INSERT INTO jobs (kind, created_by) VALUES ($1, $2)
+			RETURNING `+jobColumns,
+			kind, createdBy,
+		), j)
+		if err == nil {
+			return j, nil
+		}
+
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+			return nil, fmt.Errorf("create job: %w", err)
+		}
+
+		err = scanJob(d.Pool.QueryRow(ctx,
+			"SELECT "+jobColumns+" FROM jobs WHERE kind = $1 AND state = 'running'", kind,
+		), j)
+		if err == nil {
+			return j, ErrJobRunning
+		}
+		// The running job may have finished since the insert failed; if
+		// so, try the insert once more.
+		if err != pgx.ErrNoRows || attempt > 0 {
+			return nil, fmt.Errorf("get running job: %w", err)
+		}
+	}
+}
+
+// SetJobTotal records how many units of work the job will process.
+func (d *DB) SetJobTotal(ctx context.Context, id int64, total int) error {
+	_, err := d.Pool.Exec(ctx, "UPDATE jobs SET progress_total = $2 WHERE id = $1", id, total)
+	if err != nil {
+		return fmt.Errorf("set job total: %w", err)
+	}
+	return nil
+}
+
+// RecordJobResult stores the outcome for one author, advances progress and
+// refreshes the heartbeat. Returns whether cancellation has been requested.
+func (d *DB) RecordJobResult(ctx context.Context, id int64, r models.JobResult) (bool, error) {
+	var cancelRequested bool
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO job_results (job_id, author_id, images_stored, error)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (job_id, author_id) DO UPDATE
+			SET images_stored = EXCLUDED.images_stored, error = EXCLUDED.error, finished_at = now()
+		`, id, r.AuthorID, r.ImagesStored, r.Error)
+		if err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, `
+			UPDATE jobs SET progress_done = progress_done + 1
+			WHERE id = $1
+			RETURNING cancel_requested
+		`, id).Scan(&cancelRequested)
+	})
+	if err != nil {
+		return false, fmt.Errorf("record job result: %w", err)
+	}
+	return cancelRequested, nil
+}
+
+// FinishJob moves a running job to a terminal state.
+func (d *DB) FinishJob(ctx context.Context, id int64, state models.JobState, errMsg *string) error {
+	_, err := d.Pool.Exec(ctx, `
+		UPDATE jobs SET state = $2, error = $3, finished_at = now()
+		WHERE id = $1 AND state = 'running'
+	`, id, state, errMsg)
+	if err != nil {
+		return fmt.Errorf("finish job: %w", err)
+	}
+	return nil
+}
+
+// RequestJobCancel flags a running job for cancellation; the worker stops
+// after its current unit of work. Returns nil if the job does not exist.
+func (d *DB) RequestJobCancel(ctx context.Context, id int64) (*models.Job, error) {
+	_, err := d.Pool.Exec(ctx,
+		"UPDATE jobs SET cancel_requested = true WHERE id = $1 AND state = 'running'", id)
+	if err != nil {
+		return nil, fmt.Errorf("cancel job: %w", err)
+	}
+	return d.GetJob(ctx, id)
+}
+
+// GetJob returns a job with its per-author results, or nil if not found.
+func (d *DB) GetJob(ctx context.Context, id int64) (*models.Job, error) {
+	j := &models.Job{}
+	err := scanJob(d.Pool.QueryRow(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = $1", id), j)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+
+	rows, err := d.Pool.Query(ctx, `
+		SELECT r.author_id, a.slug, r.images_stored, r.error, r.finished_at
+		FROM job_results r
+		JOIN authors a ON a.id = r.author_id
+		WHERE r.job_id = $1
+		ORDER BY r.finished_at
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("get job results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := models.JobResult{}
+		if err := rows.Scan(&r.AuthorID, &r.AuthorSlug, &r.ImagesStored, &r.Error, &r.FinishedAt); err != nil {
+			return nil, fmt.Errorf("scan job result: %w", err)
+		}
+		j.Results = append(j.Results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get job results: %w", err)
+	}
+	return j, nil
+}
@@ -0,0 +1,402 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrChecksumMismatch is returned when an applied migration's file has been
+// edited since it was applied.
+var ErrChecksumMismatch = errors.New("migration changed after it was applied")
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version  string // file name without the .up.sql suffix, e.g. "001_initial_schema"
+	Up       string
+	Down     string // empty if there is no .down.sql file
+	Checksum string // SHA-256 of Up
+}
+
+// MigrationState describes one migration and whether it has been applied.
+type MigrationState struct {
+	Version   string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool // applied checksum differs from the file on disk
+	Missing   bool // applied, but no longer present on disk
+}
+
+// migrationLockKey identifies the session-level advisory lock held for the
+// duration of a migration run ("martyria" in ASCII).
+const migrationLockKey int64 = 0x6d61727479726961
+
+// RunMigrations applies all pending .up.sql files in version order. Each
+// migration runs in its own transaction together with its schema_migrations
+// row. Fails with ErrChecksumMismatch if an applied migration was edited.
+//
+// The whole run holds a Postgres advisory lock, so when several replicas
+// start at once one migrates and the rest wait (up to MigrationLockTimeout)
+// and then find the schema already current.
+func (d *DB) RunMigrations(ctx context.Context, migrationsFS fs.FS) error {
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return err
+	}
+
+	return d.withMigrationLock(ctx, func(conn *pgxpool.Conn) error {
+		if err := ensureMigrationsTable(ctx, conn); err != nil {
+			return err
+		}
+
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		pending := 0
+		for _, m := range migrations {
+			if rec, ok := applied[m.Version]; ok {
+				if err := checkChecksum(ctx, conn, m, rec); err != nil {
+					return err
+				}
+				continue
+			}
+
+			log.Printf("Applying migration: %s", m.Version)
+			if err := applyMigration(ctx, conn, m); err != nil {
+				return err
+			}
+			pending++
+		}
+
+		if pending == 0 {
+			log.Printf("Schema is up to date (%d migrations)", len(migrations))
+		}
+		return nil
+	})
+}
+
+func applyMigration(ctx context.Context, conn *pgxpool.Conn, m Migration) error {
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, m.Up); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx,
+			"INSERT INTO schema_migrations (version, checksum) VALUES ($1, $2)",
+			m.Version, m.Checksum,
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("apply migration %s: %w", m.Version, err)
+	}
+	return nil
+}
+
+// MigrateDown reverts applied migrations newer than target, newest first,
+// using their .down.sql files. target may be a full version
+// ("001_initial_schema") or its numeric prefix ("001") of a migration on
+// disk; "0" reverts everything, and any other target is an error. Returns
+// the reverted versions in the order they were reverted.
+// Like RunMigrations, it holds the migration advisory lock throughout.
+func (d *DB) MigrateDown(ctx context.Context, migrationsFS fs.FS, target string) ([]string, error) {
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]Migration, len(migrations))
+	targetNum := -1
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+		if target == m.Version || target == strings.SplitN(m.Version, "_", 2)[0] {
+			targetNum = versionNumber(m.Version)
+		}
+	}
+	if target == "0" {
+		targetNum = 0
+	}
+	if targetNum < 0 {
+		return nil, fmt.Errorf("migrate down: unknown target version %q", target)
+	}
+
+	var reverted []string
+	err = d.withMigrationLock(ctx, func(conn *pgxpool.Conn) error {
+		if err := ensureMigrationsTable(ctx, conn); err != nil {
+			return err
+		}
+
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		var toRevert []string
+		for version := range applied {
+			if target == "0" || versionNumber(version) > targetNum {
+				toRevert = append(toRevert, version)
+			}
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(toRevert)))
+
+		for _, version := range toRevert {
+			m, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("revert migration %s: migration file not found", version)
+			}
+			if m.Down == "" {
+				return fmt.Errorf("revert migration %s: no .down.sql file", version)
+			}
+
+			log.Printf("Reverting migration: %s", version)
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, m.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("revert migration %s: %w", version, err)
+			}
+			reverted = append(reverted, version)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// MigrationStatus lists every known migration — on disk or recorded as
+// applied — in version order.
+func (d *DB) MigrationStatus(ctx context.Context, migrationsFS fs.FS) ([]MigrationState, error) {
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(ctx, d.Pool); err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations(ctx, d.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []MigrationState
+	onDisk := make(map[string]bool, len(migrations))
+	for _, m := range migrations {
+		onDisk[m.Version] = true
+		st := MigrationState{Version: m.Version}
+		if rec, ok := applied[m.Version]; ok {
+			at := rec.appliedAt
+			st.Applied = true
+			st.AppliedAt = &at
+			st.Modified = rec.checksum != nil && *rec.checksum != m.Checksum
+		}
+		states = append(states, st)
+	}
+	for version, rec := range applied {
+		if onDisk[version] {
+			continue
+		}
+		at := rec.appliedAt
+		states = append(states, MigrationState{Version: version, Applied: true, AppliedAt: &at, Missing: true})
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states, nil
+}
+
+// PreviousVersion returns the applied version immediately before the newest
+// one, or "0" if at most one migration is applied. It is the target to pass
+// to MigrateDown to revert a single step.
+func PreviousVersion(states []MigrationState) string {
+	var applied []string
+	for _, st := range states {
+		if st.Applied {
+			applied = append(applied, st.Version)
+		}
+	}
+	if len(applied) < 2 {
+		return "0"
+	}
+	return applied[len(applied)-2]
+}
+
+// --- Internal helpers ---
+
+type appliedMigration struct {
+	appliedAt time.Time
+	checksum  *string
+}
+
+// querier is satisfied by both *pgxpool.Pool and *pgxpool.Conn.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// withMigrationLock runs fn on a dedicated connection holding the migration
+// advisory lock. It polls pg_try_advisory_lock until the lock is free or
+// MigrationLockTimeout elapses, so a stuck peer cannot block startup forever.
+func (d *DB) withMigrationLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := d.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire migration connection: %w", err)
+	}
+	defer conn.Release()
+
+	lockCtx := ctx
+	if d.MigrationLockTimeout > 0 {
+		var cancel context.CancelFunc
+		lockCtx, cancel = context.WithTimeout(ctx, d.MigrationLockTimeout)
+		defer cancel()
+	}
+
+	waiting := false
+	for {
+		var locked bool
+		if err := conn.QueryRow(lockCtx, "SELECT pg_try_advisory_lock($1)", migrationLockKey).Scan(&locked); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		if locked {
+			break
+		}
+		if !waiting {
+			log.Printf("Waiting for another instance to finish migrating...")
+			waiting = true
+		}
+		select {
+		case <-lockCtx.Done():
+			return fmt.Errorf("acquire migration lock: %w", lockCtx.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx was cancelled.
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			// Closing the session is the only other way to drop the lock.
+			log.Printf("Release migration lock: %v", err)
+			conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	return fn(conn)
+}
+
+func ensureMigrationsTable(ctx context.Context, q querier) error {
+	_, err := q.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT;
+	`)
+	if err != nil {
+		return fmt.Errorf("create migrations table: %w", err)
+	}
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, q querier) (map[string]appliedMigration, error) {
+	rows, err := q.Query(ctx, "SELECT version, applied_at, checksum FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]appliedMigration{}
+	for rows.Next() {
+		var version string
+		var rec appliedMigration
+		if err := rows.Scan(&version, &rec.appliedAt, &rec.checksum); err != nil {
+			return nil, fmt.Errorf("scan migration: %w", err)
+		}
+		applied[version] = rec
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// checkChecksum compares an applied migration against its file. Rows
+// recorded before checksums existed are backfilled with the current one.
+func checkChecksum(ctx context.Context, q querier, m Migration, rec appliedMigration) error {
+	if rec.checksum == nil {
+		_, err := q.Exec(ctx,
+			"UPDATE schema_migrations SET checksum = $2 WHERE version = $1", m.Version, m.Checksum)
+		if err != nil {
+			return fmt.Errorf("record checksum %s: %w", m.Version, err)
+		}
+		return nil
+	}
+	if *rec.checksum != m.Checksum {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, m.Version)
+	}
+	return nil
+}
+
+// loadMigrations reads all *.up.sql files (and matching *.down.sql files)
+// from the root of fsys, sorted by version.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, fmt.Errorf("glob migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	migrations := make([]Migration, 0, len(files))
+	for _, file := range files {
+		version := strings.TrimSuffix(file, ".up.sql")
+
+		up, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", file, err)
+		}
+
+		down, err := fs.ReadFile(fsys, version+".down.sql")
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("read migration %s: %w", version, err)
+		}
+
+		sum := sha256.Sum256(up)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Up:       string(up),
+			Down:     string(down),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	return migrations, nil
+}
+
+// versionNumber extracts the numeric prefix of a version ("001_foo" → 1).
+// Non-numeric versions sort as 0.
+func versionNumber(version string) int {
+	prefix, _, _ := strings.Cut(version, "_")
+	n := 0
+	for _, r := range prefix {
+		if r < '0' || r > '9' {
+			return 0
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
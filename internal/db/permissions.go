@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/martyria/martyria/internal/models"
+)
+
+const permissionColumns = `id, author_id, contact_name, organization, status,
+	date_contacted::text, date_responded::text, notes, created_at`
+
+func scanPermission(row pgx.Row, p *models.Permission) error {
+	return row.Scan(&p.ID, &p.AuthorID, &p.ContactName, &p.Organization, &p.Status,
+		&p.DateContacted, &p.DateResponded, &p.Notes, &p.CreatedAt)
+}
+
+// ListPermissions returns an author's permission records, newest first.
+func (d *DB) ListPermissions(ctx context.Context, authorID int64) ([]models.Permission, error) {
+	rows, err := d.Pool.Query(ctx, `
+		SELECT `+permissionColumns+` FROM permissions
+		WHERE author_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("list permissions: %w", err)
+	}
+	defer rows.Close()
+
+	perms := []models.Permission{}
+	for rows.Next() {
+		p := models.Permission{}
+		if err := scanPermission(rows, &p); err != nil {
+			return nil, fmt.Errorf("scan permission: %w", err)
+		}
+		perms = append(perms, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list permissions: %w", err)
+	}
+	return perms, nil
+}
+
+// GetPermission returns a permission record, or nil if not found.
+func (d *DB) GetPermission(ctx context.Context, id int64) (*models.Permission, error) {
+	p := &models.Permission{}
+	err := scanPermission(d.Pool.QueryRow(ctx,
+		"SELECT "+permissionColumns+" FROM permissions WHERE id = $1", id), p)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get permission: %w", err)
+	}
+	return p, nil
+}
+
+// CreatePermission records outreach for an author.
+func (d *DB) CreatePermission(ctx context.Context, authorID int64, in models.PermissionInput) (*models.Permission, error) {
+	p := &models.Permission{}
+	err := scanPermission(d.Pool.QueryRow(ctx, `
+		INSERT INTO permissions (author_id, contact_name, organization, status, date_contacted, date_responded, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+permissionColumns,
+		authorID, in.ContactName, in.Organization, in.Status, in.DateContacted, in.DateResponded, in.Notes,
+	), p)
+	if err != nil {
+		return nil, fmt.Errorf("create permission: %w", err)
+	}
+	return p, nil
+}
+
+// UpdatePermission replaces the writable fields of a permission record.
+// Returns nil if it does not exist.
+func (d *DB) UpdatePermission(ctx context.Context, id int64, in models.PermissionInput) (*models.Permission, error) {
+	p := &models.Permission{}
+	err := scanPermission(d.Pool.QueryRow(ctx, `
+		UPDATE permissions SET
+			contact_name = $2, organization = $3, status = $4,
+			date_contacted = $5, date_responded = $6, notes = $7
+		WHERE id = $1
+		RETURNING `+permissionColumns,
+		id, in.ContactName, in.Organization, in.Status, in.DateContacted, in.DateResponded, in.Notes,
+	), p)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("update permission: %w", err)
+	}
+	return p, nil
+}
+
+// DeletePermission removes a permission record. Returns false if it does
+// not exist.
+func (d *DB) DeletePermission(ctx context.Context, id int64) (bool, error) {
+	tag, err := d.Pool.Exec(ctx, "DELETE FROM permissions WHERE id = $1", id)
+	if err != nil {
+		return false, fmt.Errorf("delete permission: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
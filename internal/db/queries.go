@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/martyria/martyria/internal/models"
+	"github.com/martyria/martyria/internal/textfold"
 )
 
 // --- Authors ---
@@ -18,9 +20,9 @@ func (d *DB) GetAuthor(ctx context.Context, slug string) (*models.Author, error)
 	err := d.Pool.QueryRow(ctx, `
 		SELECT a.id, a.slug, a.name, a.name_original, a.title,
 			a.born_year, a.died_year, a.era, a.tradition,
-			a.bio, a.bio_short, a.canonized, a.canonized_date, a.canonized_by,
+			a.bio, a.bio_short, a.canonized, a.canonized_date::text, a.canonized_by,
 			a.feast_day_orthodox, a.feast_day_catholic, a.copyright_status,
-			a.wikipedia_url, a.wikimedia_category,
+			a.wikipedia_url, a.wikimedia_category, `+permissionFromExpr+`,
 			a.created_at, a.updated_at,
 			(SELECT COUNT(*) FROM quotes WHERE author_id = a.id) as quote_count
 		FROM authors a WHERE a.slug = $1
@@ -29,7 +31,7 @@ func (d *DB) GetAuthor(ctx context.Context, slug string) (*models.Author, error)
 		&a.BornYear, &a.DiedYear, &a.Era, &a.Tradition,
 		&a.Bio, &a.BioShort, &a.Canonized, &a.CanonizedDate, &a.CanonizedBy,
 		&a.FeastDayOrthodox, &a.FeastDayCatholic, &a.CopyrightStatus,
-		&a.WikipediaURL, &a.WikimediaCategory,
+		&a.WikipediaURL, &a.WikimediaCategory, &a.PermissionFrom,
 		&a.CreatedAt, &a.UpdatedAt,
 		&a.QuoteCount,
 	)
@@ -39,6 +41,11 @@ func (d *DB) GetAuthor(ctx context.Context, slug string) (*models.Author, error)
 		}
 		return nil, fmt.Errorf("get author: %w", err)
 	}
+
+	a.Feasts, err = d.getAuthorFeasts(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
 	return a, nil
 }
 
@@ -65,9 +72,28 @@ func (d *DB) ListAuthors(ctx context.Context, f models.AuthorFilter) ([]models.A
 		argN++
 	}
 	if f.Search != "" {
-		where = append(where, fmt.Sprintf("(a.name ILIKE $%d OR a.bio_short ILIKE $%d)", argN, argN))
-		args = append(args, "%"+f.Search+"%")
-		argN++
+		// name_original is matched on its folded (and transliterated) keys,
+		// so accents and breathings need not be typed.
+		cond := fmt.Sprintf("a.name ILIKE $%d OR a.bio_short ILIKE $%d OR a.name_original_folded LIKE $%d", argN, argN, argN+1)
+		args = append(args, containsPattern(f.Search), containsPattern(textfold.Fold(f.Search)))
+		argN += 2
+		if f.Translit {
+			cond += fmt.Sprintf(" OR a.name_original_latin LIKE $%d", argN)
+			args = append(args, containsPattern(textfold.Transliterate(f.Search)))
+			argN++
+		}
+		where = append(where, "("+cond+")")
+	}
+	if f.FeastMonth > 0 {
+		cond := fmt.Sprintf("af.month = $%d AND af.day = $%d", argN, argN+1)
+		args = append(args, f.FeastMonth, f.FeastDay)
+		argN += 2
+		if f.FeastCalendar != "" {
+			cond += fmt.Sprintf(" AND af.calendar = $%d", argN)
+			args = append(args, f.FeastCalendar)
+			argN++
+		}
+		where = append(where, "EXISTS (SELECT 1 FROM author_feasts af WHERE af.author_id = a.id AND "+cond+")")
 	}
 
 	whereClause := strings.Join(where, " AND ")
@@ -128,16 +154,18 @@ func (d *DB) GetQuote(ctx context.Context, id int64) (*models.Quote, error) {
 	err := d.Pool.QueryRow(ctx, `
 		SELECT q.id, q.author_id, q.text, q.text_original, q.language,
 			q.source_work, q.source_chapter, q.source_publisher, q.source_page, q.source_url,
-			q.license, q.verified, q.created_at, q.updated_at,
-			a.id, a.slug, a.name, a.era, a.tradition, a.bio_short, a.copyright_status
+			q.license, q.verified, q.verified_by, q.verified_at, q.created_at, q.updated_at,
+			a.id, a.slug, a.name, a.era, a.tradition, a.bio_short, a.copyright_status,
+			`+quoteRestrictedExpr+`, `+permissionFromExpr+`
 		FROM quotes q
 		JOIN authors a ON a.id = q.author_id
 		WHERE q.id = $1
 	`, id).Scan(
 		&q.ID, &q.AuthorID, &q.Text, &q.TextOriginal, &q.Language,
 		&q.SourceWork, &q.SourceChapter, &q.SourcePublisher, &q.SourcePage, &q.SourceURL,
-		&q.License, &q.Verified, &q.CreatedAt, &q.UpdatedAt,
+		&q.License, &q.Verified, &q.VerifiedBy, &q.VerifiedAt, &q.CreatedAt, &q.UpdatedAt,
 		&a.ID, &a.Slug, &a.Name, &a.Era, &a.Tradition, &a.BioShort, &a.CopyrightStatus,
+		&q.Restricted, &a.PermissionFrom,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -155,6 +183,12 @@ func (d *DB) GetQuote(ctx context.Context, id int64) (*models.Quote, error) {
 	}
 	q.Topics = topics
 
+	sources, err := d.getQuoteSources(ctx, q.ID)
+	if err != nil {
+		return nil, err
+	}
+	q.Sources = sources
+
 	return q, nil
 }
 
@@ -163,8 +197,9 @@ func (d *DB) GetRandomQuote(ctx context.Context, f models.QuoteFilter) (*models.
 	query := fmt.Sprintf(`
 		SELECT q.id, q.author_id, q.text, q.text_original, q.language,
 			q.source_work, q.source_chapter, q.source_publisher, q.source_page, q.source_url,
-			q.license, q.verified, q.created_at, q.updated_at,
-			a.id, a.slug, a.name, a.era, a.tradition, a.bio_short, a.copyright_status
+			q.license, q.verified, q.verified_by, q.verified_at, q.created_at, q.updated_at,
+			a.id, a.slug, a.name, a.era, a.tradition, a.bio_short, a.copyright_status,
+			`+quoteRestrictedExpr+`, `+permissionFromExpr+`
 		FROM quotes q
 		JOIN authors a ON a.id = q.author_id
 		%s
@@ -177,8 +212,9 @@ func (d *DB) GetRandomQuote(ctx context.Context, f models.QuoteFilter) (*models.
 	err := d.Pool.QueryRow(ctx, query, args...).Scan(
 		&q.ID, &q.AuthorID, &q.Text, &q.TextOriginal, &q.Language,
 		&q.SourceWork, &q.SourceChapter, &q.SourcePublisher, &q.SourcePage, &q.SourceURL,
-		&q.License, &q.Verified, &q.CreatedAt, &q.UpdatedAt,
+		&q.License, &q.Verified, &q.VerifiedBy, &q.VerifiedAt, &q.CreatedAt, &q.UpdatedAt,
 		&a.ID, &a.Slug, &a.Name, &a.Era, &a.Tradition, &a.BioShort, &a.CopyrightStatus,
+		&q.Restricted, &a.PermissionFrom,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -207,19 +243,47 @@ func (d *DB) ListQuotes(ctx context.Context, f models.QuoteFilter) ([]models.Quo
 		return nil, 0, fmt.Errorf("count quotes: %w", err)
 	}
 
-	offset := (f.Page - 1) * f.PerPage
+	orderBy := "q.id ASC"
+	if f.Sort == models.SortOldestFirst {
+		orderBy = "q.created_at ASC, q.id ASC"
+	}
+
+	// Search results are ranked, with the matching passage highlighted.
+	headline := "NULL::text"
 	argN := len(args) + 1
+	if f.Search != "" {
+		tsq := fmt.Sprintf("websearch_to_tsquery('english', $%d)", argN)
+		headline = "ts_headline('english', q.text, " + tsq + ", 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')"
+		args = append(args, f.Search)
+		argN++
+		if f.Sort == models.SortByID {
+			rank := fmt.Sprintf("ts_rank(q.search_vector, %s)"+
+				" + ts_rank(to_tsvector('simple', coalesce(q.text_original_folded, '')), websearch_to_tsquery('simple', $%d))",
+				tsq, argN)
+			args = append(args, textfold.Fold(f.Search))
+			argN++
+			if f.Translit {
+				rank += fmt.Sprintf(" + ts_rank(to_tsvector('simple', coalesce(q.text_original_latin, '')), websearch_to_tsquery('simple', $%d))", argN)
+				args = append(args, textfold.Transliterate(f.Search))
+				argN++
+			}
+			orderBy = rank + " DESC, q.id ASC"
+		}
+	}
+
+	offset := (f.Page - 1) * f.PerPage
 	query := fmt.Sprintf(`
-		SELECT q.id, q.author_id, q.text, q.language,
+		SELECT q.id, q.author_id, q.text, q.text_original, q.language,
 			q.source_work, q.source_chapter, q.license, q.verified,
-			q.created_at, q.updated_at,
-			a.id, a.slug, a.name, a.era, a.tradition, a.copyright_status
+			q.verified_by, q.verified_at, q.created_at, q.updated_at,
+			a.id, a.slug, a.name, a.era, a.tradition, a.copyright_status,
+			`+quoteRestrictedExpr+`, `+permissionFromExpr+`, %s
 		FROM quotes q
 		JOIN authors a ON a.id = q.author_id
 		%s
-		ORDER BY q.id ASC
+		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, where, argN, argN+1)
+	`, headline, where, orderBy, argN, argN+1)
 	args = append(args, f.PerPage, offset)
 
 	rows, err := d.Pool.Query(ctx, query, args...)
@@ -233,10 +297,11 @@ func (d *DB) ListQuotes(ctx context.Context, f models.QuoteFilter) ([]models.Quo
 		q := models.Quote{}
 		a := models.Author{}
 		if err := rows.Scan(
-			&q.ID, &q.AuthorID, &q.Text, &q.Language,
+			&q.ID, &q.AuthorID, &q.Text, &q.TextOriginal, &q.Language,
 			&q.SourceWork, &q.SourceChapter, &q.License, &q.Verified,
-			&q.CreatedAt, &q.UpdatedAt,
+			&q.VerifiedBy, &q.VerifiedAt, &q.CreatedAt, &q.UpdatedAt,
 			&a.ID, &a.Slug, &a.Name, &a.Era, &a.Tradition, &a.CopyrightStatus,
+			&q.Restricted, &a.PermissionFrom, &q.Headline,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scan quote: %w", err)
 		}
@@ -247,34 +312,55 @@ func (d *DB) ListQuotes(ctx context.Context, f models.QuoteFilter) ([]models.Quo
 	return quotes, total, nil
 }
 
-func (d *DB) GetDailyQuote(ctx context.Context, date time.Time) (*models.Quote, *string, error) {
+// GetDailyQuote returns the quote scheduled for date in tradition's
+// schedule. Unscheduled dates fall back to a deterministic pick among
+// verified, unrestricted quotes by authors the tradition honours: each
+// quote is scored by a hash of the date and its ID and the highest score
+// wins, so adding a quote only changes the days it wins outright. With
+// persist, the pick is written to daily_quotes so the date keeps its quote
+// even after later edits.
+func (d *DB) GetDailyQuote(ctx context.Context, date time.Time, tradition models.DailyTradition, persist bool) (*models.Quote, *string, error) {
 	dateStr := date.Format("2006-01-02")
 
 	// Check for a scheduled daily quote
 	var quoteID int64
 	var reason *string
 	err := d.Pool.QueryRow(ctx,
-		"SELECT quote_id, reason FROM daily_quotes WHERE date = $1", dateStr,
+		"SELECT quote_id, reason FROM daily_quotes WHERE tradition = $1 AND date = $2", tradition, dateStr,
 	).Scan(&quoteID, &reason)
 
 	if err == pgx.ErrNoRows {
-		// Fallback: deterministic pseudo-random based on date
-		var total int64
-		d.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM quotes WHERE verified = true").Scan(&total)
-		if total == 0 {
+		err = d.Pool.QueryRow(ctx, `
+			SELECT q.id FROM quotes q
+			JOIN authors a ON a.id = q.author_id
+			WHERE q.verified AND NOT `+quoteRestrictedExpr+`
+				AND ($2::text[] IS NULL OR a.tradition::text = ANY($2::text[]))
+			ORDER BY md5($1::text || ':' || q.id::text) DESC
+			LIMIT 1
+		`, dateStr, authorTraditions(tradition)).Scan(&quoteID)
+		if err == pgx.ErrNoRows {
 			return nil, nil, nil
 		}
-
-		// Simple hash of date to pick a quote
-		dayNum := date.YearDay() + date.Year()*366
-		offset := dayNum % int(total)
-
-		err = d.Pool.QueryRow(ctx, `
-			SELECT id FROM quotes WHERE verified = true ORDER BY id LIMIT 1 OFFSET $1
-		`, offset).Scan(&quoteID)
 		if err != nil {
 			return nil, nil, fmt.Errorf("daily quote fallback: %w", err)
 		}
+
+		if persist {
+			// Another replica may have stored a pick first; serve whichever won.
+			_, err = d.Pool.Exec(ctx, `
+				INSERT INTO daily_quotes (tradition, date, quote_id) VALUES ($1, $2, $3)
+				ON CONFLICT (tradition, date) DO NOTHING
+			`, tradition, dateStr, quoteID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("store daily quote: %w", err)
+			}
+			err = d.Pool.QueryRow(ctx,
+				"SELECT quote_id, reason FROM daily_quotes WHERE tradition = $1 AND date = $2", tradition, dateStr,
+			).Scan(&quoteID, &reason)
+			if err != nil {
+				return nil, nil, fmt.Errorf("daily quote: %w", err)
+			}
+		}
 	} else if err != nil {
 		return nil, nil, fmt.Errorf("daily quote: %w", err)
 	}
@@ -286,6 +372,28 @@ func (d *DB) GetDailyQuote(ctx context.Context, date time.Time) (*models.Quote,
 	return quote, reason, nil
 }
 
+// SetQuoteVerified marks a quote as verified (recording the reviewer and
+// time) or clears its verification. Returns false if the quote does not exist.
+func (d *DB) SetQuoteVerified(ctx context.Context, id int64, verified bool, reviewer string) (bool, error) {
+	var tag pgconn.CommandTag
+	var err error
+	if verified {
+		tag, err = d.Pool.Exec(ctx, `
+			UPDATE quotes SET verified = true, verified_by = $2, verified_at = now()
+			WHERE id = $1
+		`, id, reviewer)
+	} else {
+		tag, err = d.Pool.Exec(ctx, `
+			UPDATE quotes SET verified = false, verified_by = NULL, verified_at = NULL
+			WHERE id = $1
+		`, id)
+	}
+	if err != nil {
+		return false, fmt.Errorf("set quote verified: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 // --- Topics ---
 
 func (d *DB) ListTopics(ctx context.Context) ([]models.Topic, error) {
@@ -368,6 +476,24 @@ func (d *DB) GetAuthorImages(ctx context.Context, authorID int64) ([]models.Imag
 
 // --- Helpers ---
 
+// quoteRestrictedExpr is true for quotes whose author still needs a
+// 'granted' permission: fair-use authors and authors with pending
+// outreach. Expects authors aliased as a.
+const quoteRestrictedExpr = `(
+	NOT EXISTS (SELECT 1 FROM permissions p WHERE p.author_id = a.id AND p.status = 'granted')
+	AND (a.copyright_status = 'short_quote_fair_use'
+		OR EXISTS (SELECT 1 FROM permissions p WHERE p.author_id = a.id AND p.status = 'pending'))
+)`
+
+// permissionFromExpr is the organization of the author's most recent
+// granted permission, if any.
+const permissionFromExpr = `(
+	SELECT p.organization FROM permissions p
+	WHERE p.author_id = a.id AND p.status = 'granted'
+	ORDER BY p.date_responded DESC NULLS LAST, p.id DESC
+	LIMIT 1
+)`
+
 func buildQuoteWhere(f models.QuoteFilter) (string, []interface{}) {
 	where := []string{"1=1"}
 	args := []interface{}{}
@@ -403,12 +529,37 @@ func buildQuoteWhere(f models.QuoteFilter) (string, []interface{}) {
 		args = append(args, f.Language)
 		argN++
 	}
+	if f.Search != "" {
+		// The English text, or the original on its folded (and
+		// transliterated) search keys.
+		cond := fmt.Sprintf("q.search_vector @@ websearch_to_tsquery('english', $%d)"+
+			" OR to_tsvector('simple', q.text_original_folded) @@ websearch_to_tsquery('simple', $%d)", argN, argN+1)
+		args = append(args, f.Search, textfold.Fold(f.Search))
+		argN += 2
+		if f.Translit {
+			cond += fmt.Sprintf(" OR to_tsvector('simple', q.text_original_latin) @@ websearch_to_tsquery('simple', $%d)", argN)
+			args = append(args, textfold.Transliterate(f.Search))
+			argN++
+		}
+		// Restricted quotes only show their first words (and no original)
+		// to non-admins, so their full text must not be searchable either.
+		if f.Tier != models.TierAdmin {
+			cond = "(" + cond + ") AND NOT " + quoteRestrictedExpr
+		}
+		where = append(where, "("+cond+")")
+	}
 
 	return "WHERE " + strings.Join(where, " AND "), args
 }
 
+// containsPattern returns a LIKE pattern matching s anywhere, with the
+// wildcards % and _ in s taken literally.
+func containsPattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
+
 func buildAttribution(q *models.Quote, a *models.Author) *string {
-	if a.CopyrightStatus != models.CopyrightFairUse {
+	if a.CopyrightStatus != models.CopyrightFairUse && a.PermissionFrom == nil {
 		return nil
 	}
 
@@ -419,6 +570,9 @@ func buildAttribution(q *models.Quote, a *models.Author) *string {
 	if q.SourcePublisher != nil {
 		parts = append(parts, *q.SourcePublisher)
 	}
+	if a.PermissionFrom != nil {
+		parts = append(parts, "used with permission of "+*a.PermissionFrom)
+	}
 	if len(parts) == 0 {
 		return nil
 	}
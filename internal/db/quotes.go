@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/martyria/martyria/internal/models"
+)
+
+var (
+	// ErrAuthorNotFound is returned when a quote references a missing author.
+	ErrAuthorNotFound = errors.New("author not found")
+
+	// ErrUnknownTopic is returned (wrapped, with the offending slugs) when a
+	// quote is assigned a topic slug that does not exist.
+	ErrUnknownTopic = errors.New("unknown topic")
+)
+
+// CreateQuote inserts a quote with its topics in one transaction and
+// returns its ID. If in.Verified is set, reviewer is recorded as verifier.
+func (d *DB) CreateQuote(ctx context.Context, in models.QuoteInput, reviewer string) (int64, error) {
+	var id int64
+	folded, latin := searchKeys(in.TextOriginal)
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		if err := checkAuthorExists(ctx, tx, in.AuthorID); err != nil {
+			return err
+		}
+
+		err := tx.QueryRow(ctx, `
+			INSERT INTO quotes (author_id, text, text_original, language,
+				source_work, source_chapter, source_publisher, source_page, source_url,
+				license, verified, verified_by, verified_at,
+				text_original_folded, text_original_latin)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+				CASE WHEN $11 THEN $12 END,
+				CASE WHEN $11 THEN now() END,
+				$13, $14)
+			RETURNING id
+		`, in.AuthorID, in.Text, in.TextOriginal, in.Language,
+			in.SourceWork, in.SourceChapter, in.SourcePublisher, in.SourcePage, in.SourceURL,
+			in.License, in.Verified, reviewer,
+			folded, latin,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		return setQuoteTopics(ctx, tx, id, in.Topics)
+	})
+	if err != nil {
+		if errors.Is(err, ErrAuthorNotFound) || errors.Is(err, ErrUnknownTopic) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("create quote: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateQuote replaces the writable fields and topics of a quote. The
+// verifier is only changed when the verified flag flips. Returns false if
+// the quote does not exist.
+func (d *DB) UpdateQuote(ctx context.Context, id int64, in models.QuoteInput, reviewer string) (bool, error) {
+	var found bool
+	folded, latin := searchKeys(in.TextOriginal)
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		if err := checkAuthorExists(ctx, tx, in.AuthorID); err != nil {
+			return err
+		}
+
+		// updated_at is maintained by the quotes_updated_at trigger.
+		tag, err := tx.Exec(ctx, `
+			UPDATE quotes SET
+				author_id = $2, text = $3, text_original = $4, language = $5,
+				source_work = $6, source_chapter = $7, source_publisher = $8,
+				source_page = $9, source_url = $10, license = $11,
+				verified_by = CASE WHEN NOT $12 THEN NULL WHEN verified THEN verified_by ELSE $13 END,
+				verified_at = CASE WHEN NOT $12 THEN NULL WHEN verified THEN verified_at ELSE now() END,
+				verified = $12,
+				text_original_folded = $14, text_original_latin = $15
+			WHERE id = $1
+		`, id, in.AuthorID, in.Text, in.TextOriginal, in.Language,
+			in.SourceWork, in.SourceChapter, in.SourcePublisher,
+			in.SourcePage, in.SourceURL, in.License,
+			in.Verified, reviewer,
+			folded, latin,
+		)
+		if err != nil {
+			return err
+		}
+		if found = tag.RowsAffected() > 0; !found {
+			return nil
+		}
+		return setQuoteTopics(ctx, tx, id, in.Topics)
+	})
+	if err != nil {
+		if errors.Is(err, ErrAuthorNotFound) || errors.Is(err, ErrUnknownTopic) {
+			return false, err
+		}
+		return false, fmt.Errorf("update quote: %w", err)
+	}
+	return found, nil
+}
+
+// DeleteQuote removes a quote; its topic links, sources and daily schedule
+// entries cascade. Returns false if the quote does not exist.
+func (d *DB) DeleteQuote(ctx context.Context, id int64) (bool, error) {
+	tag, err := d.Pool.Exec(ctx, "DELETE FROM quotes WHERE id = $1", id)
+	if err != nil {
+		return false, fmt.Errorf("delete quote: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func checkAuthorExists(ctx context.Context, tx pgx.Tx, authorID int64) error {
+	var exists bool
+	err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM authors WHERE id = $1)", authorID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrAuthorNotFound
+	}
+	return nil
+}
+
+// setQuoteTopics replaces the quote's topic links with the given slugs.
+func setQuoteTopics(ctx context.Context, tx pgx.Tx, quoteID int64, slugs []string) error {
+	if slugs == nil {
+		slugs = []string{}
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT s FROM unnest($1::text[]) s
+		WHERE NOT EXISTS (SELECT 1 FROM topics t WHERE t.slug = s)
+	`, slugs)
+	if err != nil {
+		return err
+	}
+	missing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownTopic, strings.Join(missing, ", "))
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM quote_topics WHERE quote_id = $1", quoteID); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO quote_topics (quote_id, topic_id)
+		SELECT $1, id FROM topics WHERE slug = ANY($2)
+	`, quoteID, slugs)
+	return err
+}
@@ -0,0 +1,286 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/martyria/martyria/internal/models"
+)
+
+// ErrQuoteNotFound is returned (wrapped, with the offending IDs) when a
+// schedule references quotes that do not exist.
+var ErrQuoteNotFound = errors.New("quote not found")
+
+// ScheduleDailyQuote assigns quoteID to every date from..to (inclusive) in
+// tradition's schedule, replacing whatever was scheduled. Returns the number
+// of dates written.
+func (d *DB) ScheduleDailyQuote(ctx context.Context, tradition models.DailyTradition, from, to time.Time, quoteID int64, reason *string) (int64, error) {
+	var n int64
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		if err := checkQuotesExist(ctx, tx, []int64{quoteID}); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO daily_quotes (tradition, date, quote_id, reason)
+			SELECT $1, day::date, $4, $5 FROM generate_series($2::date, $3::date, '1 day') day
+			ON CONFLICT (tradition, date) DO UPDATE SET quote_id = EXCLUDED.quote_id, reason = EXCLUDED.reason
+		`, tradition, from, to, quoteID, reason)
+		n = tag.RowsAffected()
+		return err
+	})
+	if errors.Is(err, ErrQuoteNotFound) {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("schedule daily quote: %w", err)
+	}
+	return n, nil
+}
+
+// ImportDailySchedule writes entries to tradition's schedule in one
+// transaction, replacing existing assignments for the same dates. Entries
+// must have valid dates.
+func (d *DB) ImportDailySchedule(ctx context.Context, tradition models.DailyTradition, entries []models.DailyScheduleEntry) error {
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.QuoteID)
+	}
+
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		if err := checkQuotesExist(ctx, tx, ids); err != nil {
+			return err
+		}
+
+		batch := &pgx.Batch{}
+		for _, e := range entries {
+			batch.Queue(`
+				INSERT INTO daily_quotes (tradition, date, quote_id, reason) VALUES ($1, $2, $3, $4)
+				ON CONFLICT (tradition, date) DO UPDATE SET quote_id = EXCLUDED.quote_id, reason = EXCLUDED.reason
+			`, tradition, e.Date, e.QuoteID, e.Reason)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if errors.Is(err, ErrQuoteNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("import daily schedule: %w", err)
+	}
+	return nil
+}
+
+// FillDailySchedule inserts entries for dates that have nothing scheduled in
+// tradition's schedule, leaving existing entries untouched, and returns how
+// many were inserted.
+func (d *DB) FillDailySchedule(ctx context.Context, tradition models.DailyTradition, entries []models.DailyScheduleEntry) (int64, error) {
+	var n int64
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, e := range entries {
+			batch.Queue(`
+				INSERT INTO daily_quotes (tradition, date, quote_id, reason) VALUES ($1, $2, $3, $4)
+				ON CONFLICT (tradition, date) DO NOTHING
+			`, tradition, e.Date, e.QuoteID, e.Reason).Exec(func(tag pgconn.CommandTag) error {
+				n += tag.RowsAffected()
+				return nil
+			})
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("fill daily schedule: %w", err)
+	}
+	return n, nil
+}
+
+// DailyCandidate is a quote the planner may schedule automatically.
+type DailyCandidate struct {
+	QuoteID    int64
+	AuthorID   int64
+	AuthorName string
+	Canonized  bool
+	Feasts     []TraditionFeast // the author's author_feasts rows
+}
+
+// DailyCandidates returns verified quotes by authors tradition honours that
+// are not restricted by an outstanding permission, in ID order.
+func (d *DB) DailyCandidates(ctx context.Context, tradition models.DailyTradition) ([]DailyCandidate, error) {
+	rows, err := d.Pool.Query(ctx, `
+		SELECT q.id, a.id, a.name, a.canonized
+		FROM quotes q
+		JOIN authors a ON a.id = q.author_id
+		WHERE q.verified AND NOT `+quoteRestrictedExpr+`
+			AND ($1::text[] IS NULL OR a.tradition::text = ANY($1::text[]))
+		ORDER BY q.id
+	`, authorTraditions(tradition))
+	if err != nil {
+		return nil, fmt.Errorf("daily candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var cands []DailyCandidate
+	var authorIDs []int64
+	seen := map[int64]bool{}
+	for rows.Next() {
+		c := DailyCandidate{}
+		if err := rows.Scan(&c.QuoteID, &c.AuthorID, &c.AuthorName, &c.Canonized); err != nil {
+			return nil, fmt.Errorf("scan daily candidate: %w", err)
+		}
+		cands = append(cands, c)
+		if !seen[c.AuthorID] {
+			seen[c.AuthorID] = true
+			authorIDs = append(authorIDs, c.AuthorID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("daily candidates: %w", err)
+	}
+	rows.Close()
+
+	feasts, err := d.feastsByAuthor(ctx, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cands {
+		cands[i].Feasts = feasts[cands[i].AuthorID]
+	}
+	return cands, nil
+}
+
+// ScheduledQuote is a daily_quotes row with its quote's author.
+type ScheduledQuote struct {
+	Date     time.Time
+	QuoteID  int64
+	AuthorID int64
+}
+
+// ScheduledQuotes returns tradition's schedule from..to (inclusive) in date
+// order.
+func (d *DB) ScheduledQuotes(ctx context.Context, tradition models.DailyTradition, from, to time.Time) ([]ScheduledQuote, error) {
+	rows, err := d.Pool.Query(ctx, `
+		SELECT dq.date, dq.quote_id, q.author_id
+		FROM daily_quotes dq
+		JOIN quotes q ON q.id = dq.quote_id
+		WHERE dq.tradition = $1 AND dq.date BETWEEN $2::date AND $3::date
+		ORDER BY dq.date
+	`, tradition, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("scheduled quotes: %w", err)
+	}
+	defer rows.Close()
+
+	var days []ScheduledQuote
+	for rows.Next() {
+		s := ScheduledQuote{}
+		if err := rows.Scan(&s.Date, &s.QuoteID, &s.AuthorID); err != nil {
+			return nil, fmt.Errorf("scan scheduled quote: %w", err)
+		}
+		days = append(days, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scheduled quotes: %w", err)
+	}
+	return days, nil
+}
+
+// ClearDailyQuotes removes quotes scheduled from..to (inclusive) in
+// tradition's schedule and returns how many were removed.
+func (d *DB) ClearDailyQuotes(ctx context.Context, tradition models.DailyTradition, from, to time.Time) (int64, error) {
+	tag, err := d.Pool.Exec(ctx,
+		"DELETE FROM daily_quotes WHERE tradition = $1 AND date BETWEEN $2::date AND $3::date", tradition, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("clear daily quotes: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ListDailySchedule returns the quotes scheduled from..to (inclusive) in
+// tradition's schedule, in date order.
+func (d *DB) ListDailySchedule(ctx context.Context, tradition models.DailyTradition, from, to time.Time) ([]models.QuoteOfTheDay, error) {
+	rows, err := d.Pool.Query(ctx, `
+		SELECT dq.date::text, dq.reason,
+			q.id, q.author_id, q.text, q.language,
+			q.source_work, q.source_chapter, q.license, q.verified,
+			q.verified_by, q.verified_at, q.created_at, q.updated_at,
+			a.id, a.slug, a.name, a.era, a.tradition, a.copyright_status,
+			`+quoteRestrictedExpr+`, `+permissionFromExpr+`
+		FROM daily_quotes dq
+		JOIN quotes q ON q.id = dq.quote_id
+		JOIN authors a ON a.id = q.author_id
+		WHERE dq.tradition = $1 AND dq.date BETWEEN $2::date AND $3::date
+		ORDER BY dq.date
+	`, tradition, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list daily schedule: %w", err)
+	}
+	defer rows.Close()
+
+	days := []models.QuoteOfTheDay{}
+	for rows.Next() {
+		day := models.QuoteOfTheDay{Tradition: tradition}
+		q := &day.Quote
+		a := &models.Author{}
+		var reason *string
+		if err := rows.Scan(
+			&day.Date, &reason,
+			&q.ID, &q.AuthorID, &q.Text, &q.Language,
+			&q.SourceWork, &q.SourceChapter, &q.License, &q.Verified,
+			&q.VerifiedBy, &q.VerifiedAt, &q.CreatedAt, &q.UpdatedAt,
+			&a.ID, &a.Slug, &a.Name, &a.Era, &a.Tradition, &a.CopyrightStatus,
+			&q.Restricted, &a.PermissionFrom,
+		); err != nil {
+			return nil, fmt.Errorf("scan daily quote: %w", err)
+		}
+		if reason != nil {
+			day.Reason = *reason
+		}
+		q.Author = a
+		q.Attribution = buildAttribution(q, a)
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list daily schedule: %w", err)
+	}
+	return days, nil
+}
+
+// authorTraditions returns the author traditions t draws on as a query
+// argument; nil (NULL) places no restriction.
+func authorTraditions(t models.DailyTradition) []string {
+	var strs []string
+	for _, at := range t.AuthorTraditions() {
+		strs = append(strs, string(at))
+	}
+	return strs
+}
+
+// checkQuotesExist returns ErrQuoteNotFound listing any missing IDs.
+func checkQuotesExist(ctx context.Context, tx pgx.Tx, ids []int64) error {
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT u.id FROM unnest($1::bigint[]) AS u(id)
+		WHERE NOT EXISTS (SELECT 1 FROM quotes q WHERE q.id = u.id)
+		ORDER BY u.id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	missing, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	strs := make([]string, len(missing))
+	for i, id := range missing {
+		strs[i] = strconv.FormatInt(id, 10)
+	}
+	return fmt.Errorf("%w: %s", ErrQuoteNotFound, strings.Join(strs, ", "))
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/martyria/martyria/internal/textfold"
+)
+
+// searchKeys returns the folded and transliterated search keys stored
+// alongside an original-language text, or nils when there is none.
+func searchKeys(original *string) (folded, latin *string) {
+	if original == nil {
+		return nil, nil
+	}
+	f, l := textfold.Fold(*original), textfold.Transliterate(*original)
+	return &f, &l
+}
+
+// BackfillSearchKeys computes the original-language search keys of quotes
+// and authors that lack them, or of every row when force is set. Returns
+// the number of rows updated. The rows' updated_at is left as it was (see
+// migration 009): the keys are derived, not content.
+func (d *DB) BackfillSearchKeys(ctx context.Context, force bool) (int, error) {
+	tables := []struct{ table, column string }{
+		{"quotes", "text_original"},
+		{"authors", "name_original"},
+	}
+
+	var n int
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		for _, t := range tables {
+			rows, err := tx.Query(ctx, fmt.Sprintf(`
+				SELECT id, %[1]s FROM %[2]s
+				WHERE %[1]s IS NOT NULL AND ($1 OR %[1]s_folded IS NULL)
+				ORDER BY id
+				FOR UPDATE
+			`, t.column, t.table), force)
+			if err != nil {
+				return err
+			}
+			ids, originals := []int64{}, []string{}
+			for rows.Next() {
+				var id int64
+				var original string
+				if err := rows.Scan(&id, &original); err != nil {
+					rows.Close()
+					return err
+				}
+				ids, originals = append(ids, id), append(originals, original)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			batch := &pgx.Batch{}
+			for i, id := range ids {
+				folded, latin := searchKeys(&originals[i])
+				batch.Queue(fmt.Sprintf(
+					"UPDATE %[2]s SET %[1]s_folded = $2, %[1]s_latin = $3 WHERE id = $1", t.column, t.table,
+				), id, folded, latin)
+			}
+			if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+				return err
+			}
+			n += len(ids)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("backfill search keys: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/martyria/martyria/internal/models"
+)
+
+const quoteSourceColumns = "id, quote_id, source_type, source_title, publisher, year, page, url, license"
+
+func scanQuoteSource(row pgx.Row, s *models.QuoteSource) error {
+	return row.Scan(&s.ID, &s.QuoteID, &s.SourceType, &s.SourceTitle,
+		&s.Publisher, &s.Year, &s.Page, &s.URL, &s.License)
+}
+
+// AddQuoteSources inserts provenance records for a quote in one
+// transaction. Returns nil if the quote does not exist.
+func (d *DB) AddQuoteSources(ctx context.Context, quoteID int64, in []models.QuoteSourceInput) ([]models.QuoteSource, error) {
+	var added []models.QuoteSource
+	err := pgx.BeginFunc(ctx, d.Pool, func(tx pgx.Tx) error {
+		// Lock the quote so it cannot be deleted mid-insert.
+		var id int64
+		err := tx.QueryRow(ctx, "SELECT id FROM quotes WHERE id = $1 FOR SHARE", quoteID).Scan(&id)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		added = make([]models.QuoteSource, 0, len(in))
+		for _, src := range in {
+			s := models.QuoteSource{}
+			err := scanQuoteSource(tx.QueryRow(ctx, `
+				INSERT INTO quote_sources (quote_id, source_type, source_title, publisher, year, page, url, license)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING `+quoteSourceColumns,
+				quoteID, src.SourceType, src.SourceTitle, src.Publisher, src.Year, src.Page, src.URL, src.License,
+			), &s)
+			if err != nil {
+				return err
+			}
+			added = append(added, s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("add quote sources: %w", err)
+	}
+	return added, nil
+}
+
+// DeleteQuoteSource removes one provenance record from a quote. Returns
+// false if no such record exists for that quote.
+func (d *DB) DeleteQuoteSource(ctx context.Context, quoteID, sourceID int64) (bool, error) {
+	tag, err := d.Pool.Exec(ctx,
+		"DELETE FROM quote_sources WHERE id = $1 AND quote_id = $2", sourceID, quoteID)
+	if err != nil {
+		return false, fmt.Errorf("delete quote source: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (d *DB) getQuoteSources(ctx context.Context, quoteID int64) ([]models.QuoteSource, error) {
+	rows, err := d.Pool.Query(ctx, `
+		SELECT `+quoteSourceColumns+` FROM quote_sources
+		WHERE quote_id = $1
+		ORDER BY year ASC NULLS LAST, id ASC
+	`, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("get quote sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []models.QuoteSource
+	for rows.Next() {
+		s := models.QuoteSource{}
+		if err := scanQuoteSource(rows, &s); err != nil {
+			return nil, fmt.Errorf("scan quote source: %w", err)
+		}
+		sources = append(sources, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get quote sources: %w", err)
+	}
+	return sources, nil
+}
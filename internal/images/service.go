@@ -8,23 +8,22 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/martyria/martyria/internal/models"
+	"github.com/martyria/martyria/internal/storage"
 )
 
 // Service orchestrates image discovery, downloading, and storage.
 type Service struct {
 	Pool      *pgxpool.Pool
 	Wikimedia *WikimediaClient
-	ImageDir  string
-	BaseURL   string
+	Storage   storage.Storage
 }
 
-// NewService creates an image service.
-func NewService(pool *pgxpool.Pool, imageDir, baseURL string) *Service {
+// NewService creates an image service that stores files in store.
+func NewService(pool *pgxpool.Pool, store storage.Storage) *Service {
 	return &Service{
 		Pool:      pool,
 		Wikimedia: NewWikimediaClient(),
-		ImageDir:  imageDir,
-		BaseURL:   baseURL,
+		Storage:   store,
 	}
 }
 
@@ -83,7 +82,7 @@ func (s *Service) FetchForAuthor(ctx context.Context, author models.Author) (int
 		img := scored[i]
 
 		// Download full image
-		localPath, err := s.Wikimedia.DownloadImage(ctx, img.image.URL, s.ImageDir, author.Slug)
+		localPath, err := s.Wikimedia.DownloadImage(ctx, img.image.URL, s.Storage, author.Slug)
 		if err != nil {
 			log.Printf("Failed to download image for %s: %v", author.Slug, err)
 			continue
@@ -92,7 +91,7 @@ func (s *Service) FetchForAuthor(ctx context.Context, author models.Author) (int
 		// Download thumbnail
 		var thumbPath *string
 		if img.image.ThumbURL != "" {
-			tp, err := s.Wikimedia.DownloadThumbnail(ctx, img.image.ThumbURL, s.ImageDir, author.Slug)
+			tp, err := s.Wikimedia.DownloadThumbnail(ctx, img.image.ThumbURL, s.Storage, author.Slug)
 			if err == nil {
 				thumbPath = &tp
 			}
@@ -135,8 +134,24 @@ func (s *Service) FetchForAuthor(ctx context.Context, author models.Author) (int
 	return stored, nil
 }
 
+// AuthorResult is the outcome of fetching images for one author.
+type AuthorResult struct {
+	Author models.Author
+	Stored int
+	Err    error
+}
+
 // FetchAllAuthors fetches images for all authors that don't have any yet.
 func (s *Service) FetchAllAuthors(ctx context.Context) error {
+	authors, err := s.AuthorsWithoutImages(ctx)
+	if err != nil {
+		return err
+	}
+	return s.Harvest(ctx, authors, nil)
+}
+
+// AuthorsWithoutImages lists authors that have no stored images.
+func (s *Service) AuthorsWithoutImages(ctx context.Context) ([]models.Author, error) {
 	rows, err := s.Pool.Query(ctx, `
 		SELECT a.id, a.slug, a.name, a.title, a.era, a.tradition,
 			a.canonized, a.copyright_status, a.wikimedia_category
@@ -145,7 +160,7 @@ func (s *Service) FetchAllAuthors(ctx context.Context) error {
 		ORDER BY a.id
 	`)
 	if err != nil {
-		return fmt.Errorf("list authors without images: %w", err)
+		return nil, fmt.Errorf("list authors without images: %w", err)
 	}
 	defer rows.Close()
 
@@ -156,18 +171,36 @@ func (s *Service) FetchAllAuthors(ctx context.Context) error {
 			&a.ID, &a.Slug, &a.Name, &a.Title, &a.Era, &a.Tradition,
 			&a.Canonized, &a.CopyrightStatus, &a.WikimediaCategory,
 		); err != nil {
-			return fmt.Errorf("scan author: %w", err)
+			return nil, fmt.Errorf("scan author: %w", err)
 		}
 		authors = append(authors, a)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list authors without images: %w", err)
+	}
+	return authors, nil
+}
 
+// Harvest fetches images for each author in turn. A failure for one author
+// doesn't abort the run; onResult (if non-nil) is called after every author
+// and may return an error to stop early.
+func (s *Service) Harvest(ctx context.Context, authors []models.Author, onResult func(AuthorResult) error) error {
 	log.Printf("Fetching images for %d authors...", len(authors))
 
 	for _, author := range authors {
-		if _, err := s.FetchForAuthor(ctx, author); err != nil {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("fetch aborted: %w", err)
+		}
+		stored, err := s.FetchForAuthor(ctx, author)
+		if err != nil {
 			log.Printf("Error fetching images for %s: %v", author.Slug, err)
 			// Continue with next author, don't abort
 		}
+		if onResult != nil {
+			if err := onResult(AuthorResult{Author: author, Stored: stored, Err: err}); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
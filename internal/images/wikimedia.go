@@ -8,10 +8,11 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
+	"path"
 	"strings"
 	"time"
+
+	"github.com/martyria/martyria/internal/storage"
 )
 
 // WikimediaClient searches and downloads images from Wikimedia Commons.
@@ -122,85 +123,40 @@ func (wc *WikimediaClient) SearchByName(ctx context.Context, query string, limit
 	return wc.parseImageInfoResponse(body)
 }
 
-// DownloadImage downloads an image to the local filesystem.
-// Returns the local path relative to imageDir.
-func (wc *WikimediaClient) DownloadImage(ctx context.Context, imageURL, imageDir, authorSlug string) (string, error) {
-	// Create author directory
-	authorDir := filepath.Join(imageDir, authorSlug)
-	if err := os.MkdirAll(authorDir, 0o755); err != nil {
-		return "", fmt.Errorf("mkdir: %w", err)
-	}
+// DownloadImage downloads an image into store under "{authorSlug}/{filename}".
+// Returns the storage key.
+func (wc *WikimediaClient) DownloadImage(ctx context.Context, imageURL string, store storage.Storage, authorSlug string) (string, error) {
+	return wc.download(ctx, imageURL, store, authorSlug)
+}
+
+// DownloadThumbnail downloads a thumbnail into "{authorSlug}/thumbs/{filename}".
+func (wc *WikimediaClient) DownloadThumbnail(ctx context.Context, thumbURL string, store storage.Storage, authorSlug string) (string, error) {
+	return wc.download(ctx, thumbURL, store, authorSlug+"/thumbs")
+}
 
+func (wc *WikimediaClient) download(ctx context.Context, fileURL string, store storage.Storage, prefix string) (string, error) {
 	// Determine filename from URL
-	parsed, err := url.Parse(imageURL)
+	parsed, err := url.Parse(fileURL)
 	if err != nil {
 		return "", fmt.Errorf("parse URL: %w", err)
 	}
-	filename := filepath.Base(parsed.Path)
+	filename := path.Base(parsed.Path)
 	// Sanitize filename
 	filename = strings.ReplaceAll(filename, " ", "_")
-	localPath := filepath.Join(authorSlug, filename)
-	fullPath := filepath.Join(imageDir, localPath)
+	key := prefix + "/" + filename
 
 	// Skip if already downloaded
-	if _, err := os.Stat(fullPath); err == nil {
-		log.Printf("Image already exists: %s", localPath)
-		return localPath, nil
-	}
-
-	// Download
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	exists, err := store.Exists(ctx, key)
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return "", fmt.Errorf("check existing: %w", err)
 	}
-	req.Header.Set("User-Agent", wc.UserAgent)
-
-	resp, err := wc.HTTPClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("download: %w", err)
+	if exists {
+		log.Printf("Image already exists: %s", key)
+		return key, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("download status %d for %s", resp.StatusCode, imageURL)
-	}
-
-	file, err := os.Create(fullPath)
-	if err != nil {
-		return "", fmt.Errorf("create file: %w", err)
-	}
-	defer file.Close()
-
-	written, err := io.Copy(file, resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("write file: %w", err)
-	}
-
-	log.Printf("Downloaded %s (%d bytes)", localPath, written)
-	return localPath, nil
-}
-
-// DownloadThumbnail downloads a thumbnail version of an image.
-func (wc *WikimediaClient) DownloadThumbnail(ctx context.Context, thumbURL, imageDir, authorSlug string) (string, error) {
-	authorDir := filepath.Join(imageDir, authorSlug, "thumbs")
-	if err := os.MkdirAll(authorDir, 0o755); err != nil {
-		return "", fmt.Errorf("mkdir thumbs: %w", err)
-	}
-
-	parsed, err := url.Parse(thumbURL)
-	if err != nil {
-		return "", fmt.Errorf("parse thumb URL: %w", err)
-	}
-	filename := filepath.Base(parsed.Path)
-	filename = strings.ReplaceAll(filename, " ", "_")
-	localPath := filepath.Join(authorSlug, "thumbs", filename)
-	fullPath := filepath.Join(imageDir, localPath)
-
-	if _, err := os.Stat(fullPath); err == nil {
-		return localPath, nil
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, thumbURL, nil)
+	// Download
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("create request: %w", err)
 	}
@@ -208,22 +164,20 @@ func (wc *WikimediaClient) DownloadThumbnail(ctx context.Context, thumbURL, imag
 
 	resp, err := wc.HTTPClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("download thumb: %w", err)
+		return "", fmt.Errorf("download: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("thumb status %d", resp.StatusCode)
+		return "", fmt.Errorf("download status %d for %s", resp.StatusCode, fileURL)
 	}
 
-	file, err := os.Create(fullPath)
-	if err != nil {
-		return "", fmt.Errorf("create thumb file: %w", err)
+	if err := store.Put(ctx, key, resp.Body, resp.ContentLength, resp.Header.Get("Content-Type")); err != nil {
+		return "", fmt.Errorf("store: %w", err)
 	}
-	defer file.Close()
 
-	io.Copy(file, resp.Body)
-	return localPath, nil
+	log.Printf("Downloaded %s (%d bytes)", key, resp.ContentLength)
+	return key, nil
 }
 
 // --- Internal helpers ---
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type AuthorEra string
 
@@ -35,6 +38,35 @@ const (
 	CopyrightCCBYSA          CopyrightStatus = "cc_by_sa"
 )
 
+// Valid reports whether e is one of the author_era enum values.
+func (e AuthorEra) Valid() bool {
+	switch e {
+	case EraApostolic, EraAnteNicene, EraNicene, EraPostNicene,
+		EraMedieval, EraReformation, EraModern, EraContemporary:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether t is one of the author_tradition enum values.
+func (t AuthorTradition) Valid() bool {
+	switch t {
+	case TraditionPreSchism, TraditionOrthodox, TraditionCatholic,
+		TraditionProtestant, TraditionAnglican, TraditionNonDenominational:
+		return true
+	}
+	return false
+}
+
+// Valid reports whether c is one of the copyright_status enum values.
+func (c CopyrightStatus) Valid() bool {
+	switch c {
+	case CopyrightPublicDomain, CopyrightFairUse, CopyrightPermissionGrant, CopyrightCCBYSA:
+		return true
+	}
+	return false
+}
+
 type Author struct {
 	ID               int64           `json:"id"`
 	Slug             string          `json:"slug"`
@@ -55,6 +87,8 @@ type Author struct {
 	CopyrightStatus  CopyrightStatus `json:"copyright_status"`
 	WikipediaURL     *string         `json:"wikipedia_url,omitempty"`
 	WikimediaCategory *string        `json:"wikimedia_category,omitempty"`
+	PermissionFrom   *string         `json:"permission_from,omitempty"` // Organization that granted permission
+	Feasts           []AuthorFeast   `json:"feasts,omitempty"`
 	QuoteCount       int             `json:"quote_count,omitempty"`
 	ImageURL         *string         `json:"image_url,omitempty"`
 	PrimaryImage     *Image          `json:"primary_image,omitempty"`
@@ -62,6 +96,53 @@ type Author struct {
 	UpdatedAt        time.Time       `json:"updated_at"`
 }
 
+// AuthorInput is the writable subset of Author accepted by the admin API.
+// Slug is generated from Name when empty on create.
+type AuthorInput struct {
+	Slug              string          `json:"slug"`
+	Name              string          `json:"name"`
+	NameOriginal      *string         `json:"name_original"`
+	Title             *string         `json:"title"`
+	BornYear          *int            `json:"born_year"`
+	DiedYear          *int            `json:"died_year"`
+	Era               AuthorEra       `json:"era"`
+	Tradition         AuthorTradition `json:"tradition"`
+	Bio               *string         `json:"bio"`
+	BioShort          *string         `json:"bio_short"`
+	Canonized         bool            `json:"canonized"`
+	CanonizedDate     *string         `json:"canonized_date"` // YYYY-MM-DD
+	CanonizedBy       *string         `json:"canonized_by"`
+	FeastDayOrthodox  *string         `json:"feast_day_orthodox"`
+	FeastDayCatholic  *string         `json:"feast_day_catholic"`
+	CopyrightStatus   CopyrightStatus `json:"copyright_status"`
+	WikipediaURL      *string         `json:"wikipedia_url"`
+	WikimediaCategory *string         `json:"wikimedia_category"`
+}
+
+// Input returns the writable fields of a, e.g. as the base for a PATCH.
+func (a *Author) Input() AuthorInput {
+	return AuthorInput{
+		Slug:              a.Slug,
+		Name:              a.Name,
+		NameOriginal:      a.NameOriginal,
+		Title:             a.Title,
+		BornYear:          a.BornYear,
+		DiedYear:          a.DiedYear,
+		Era:               a.Era,
+		Tradition:         a.Tradition,
+		Bio:               a.Bio,
+		BioShort:          a.BioShort,
+		Canonized:         a.Canonized,
+		CanonizedDate:     a.CanonizedDate,
+		CanonizedBy:       a.CanonizedBy,
+		FeastDayOrthodox:  a.FeastDayOrthodox,
+		FeastDayCatholic:  a.FeastDayCatholic,
+		CopyrightStatus:   a.CopyrightStatus,
+		WikipediaURL:      a.WikipediaURL,
+		WikimediaCategory: a.WikimediaCategory,
+	}
+}
+
 type Topic struct {
 	ID          int64   `json:"id"`
 	Slug        string  `json:"slug"`
@@ -71,28 +152,122 @@ type Topic struct {
 }
 
 type Quote struct {
-	ID              int64   `json:"id"`
-	AuthorID        int64   `json:"author_id"`
-	Text            string  `json:"text"`
-	TextOriginal    *string `json:"text_original,omitempty"`
-	Language        string  `json:"language"`
-	SourceWork      *string `json:"source_work,omitempty"`
-	SourceChapter   *string `json:"source_chapter,omitempty"`
-	SourcePublisher *string `json:"source_publisher,omitempty"`
-	SourcePage      *string `json:"source_page,omitempty"`
-	SourceURL       *string `json:"source_url,omitempty"`
-	License         string  `json:"license"`
-	Verified        bool    `json:"verified"`
+	ID              int64      `json:"id"`
+	AuthorID        int64      `json:"author_id"`
+	Text            string     `json:"text"`
+	TextOriginal    *string    `json:"text_original,omitempty"`
+	Language        string     `json:"language"`
+	SourceWork      *string    `json:"source_work,omitempty"`
+	SourceChapter   *string    `json:"source_chapter,omitempty"`
+	SourcePublisher *string    `json:"source_publisher,omitempty"`
+	SourcePage      *string    `json:"source_page,omitempty"`
+	SourceURL       *string    `json:"source_url,omitempty"`
+	License         string     `json:"license"`
+	Verified        bool       `json:"verified"`
+	VerifiedBy      *string    `json:"verified_by,omitempty"`
+	VerifiedAt      *time.Time `json:"verified_at,omitempty"`
 
 	// Joined fields
-	Author      *Author  `json:"author,omitempty"`
-	Topics      []Topic  `json:"topics,omitempty"`
-	Attribution *string  `json:"attribution,omitempty"` // Computed for fair-use quotes
+	Author      *Author       `json:"author,omitempty"`
+	Topics      []Topic       `json:"topics,omitempty"`
+	Sources     []QuoteSource `json:"sources,omitempty"`     // Only on single-quote responses
+	Attribution *string       `json:"attribution,omitempty"` // Computed for fair-use quotes
+
+	// Headline is the matching passage of Text, with search terms wrapped
+	// in <mark>; only set on search results (QuoteFilter.Search).
+	Headline *string `json:"headline,omitempty"`
+
+	// Restricted is set when the author's permission is still outstanding;
+	// public responses then carry a truncated text.
+	Restricted bool `json:"restricted,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// QuoteInput is the writable subset of Quote accepted by the admin API,
+// with topics given as slugs.
+type QuoteInput struct {
+	AuthorID        int64    `json:"author_id"`
+	Text            string   `json:"text"`
+	TextOriginal    *string  `json:"text_original"`
+	Language        string   `json:"language"`
+	SourceWork      *string  `json:"source_work"`
+	SourceChapter   *string  `json:"source_chapter"`
+	SourcePublisher *string  `json:"source_publisher"`
+	SourcePage      *string  `json:"source_page"`
+	SourceURL       *string  `json:"source_url"`
+	License         string   `json:"license"`
+	Verified        bool     `json:"verified"`
+	Topics          []string `json:"topics"`
+}
+
+// Input returns the writable fields of q, e.g. as the base for a PATCH.
+func (q *Quote) Input() QuoteInput {
+	in := QuoteInput{
+		AuthorID:        q.AuthorID,
+		Text:            q.Text,
+		TextOriginal:    q.TextOriginal,
+		Language:        q.Language,
+		SourceWork:      q.SourceWork,
+		SourceChapter:   q.SourceChapter,
+		SourcePublisher: q.SourcePublisher,
+		SourcePage:      q.SourcePage,
+		SourceURL:       q.SourceURL,
+		License:         q.License,
+		Verified:        q.Verified,
+		Topics:          []string{},
+	}
+	for _, t := range q.Topics {
+		in.Topics = append(in.Topics, t.Slug)
+	}
+	return in
+}
+
+type QuoteSourceType string
+
+const (
+	SourceTypeBook         QuoteSourceType = "book"
+	SourceTypeOralTeaching QuoteSourceType = "oral_teaching"
+	SourceTypeLetter       QuoteSourceType = "letter"
+	SourceTypeHomily       QuoteSourceType = "homily"
+	SourceTypeSermon       QuoteSourceType = "sermon"
+)
+
+// Valid reports whether t is one of the documented quote_sources.source_type values.
+func (t QuoteSourceType) Valid() bool {
+	switch t {
+	case SourceTypeBook, SourceTypeOralTeaching, SourceTypeLetter, SourceTypeHomily, SourceTypeSermon:
+		return true
+	}
+	return false
+}
+
+// QuoteSource is one provenance record for a quote, e.g. the critical
+// edition and the translation it was taken from.
+type QuoteSource struct {
+	ID          int64           `json:"id"`
+	QuoteID     int64           `json:"quote_id"`
+	SourceType  QuoteSourceType `json:"source_type"`
+	SourceTitle string          `json:"source_title"`
+	Publisher   *string         `json:"publisher,omitempty"`
+	Year        *int            `json:"year,omitempty"`
+	Page        *string         `json:"page,omitempty"`
+	URL         *string         `json:"url,omitempty"`
+	License     *string         `json:"license,omitempty"`
+}
+
+// QuoteSourceInput is the writable subset of QuoteSource.
+type QuoteSourceInput struct {
+	SourceType  QuoteSourceType `json:"source_type"`
+	SourceTitle string          `json:"source_title"`
+	Publisher   *string         `json:"publisher"`
+	Year        *int            `json:"year"`
+	Page        *string         `json:"page"`
+	URL         *string         `json:"url"`
+	License     *string         `json:"license"`
+}
+
 type ImageSourceType string
 
 const (
@@ -138,6 +313,128 @@ type Image struct {
 	CreatedAt         time.Time       `json:"created_at"`
 }
 
+type PermissionStatus string
+
+const (
+	PermissionPending    PermissionStatus = "pending"
+	PermissionGranted    PermissionStatus = "granted"
+	PermissionDenied     PermissionStatus = "denied"
+	PermissionNoResponse PermissionStatus = "no_response"
+)
+
+// Valid reports whether s is one of the permissions.status values.
+func (s PermissionStatus) Valid() bool {
+	switch s {
+	case PermissionPending, PermissionGranted, PermissionDenied, PermissionNoResponse:
+		return true
+	}
+	return false
+}
+
+// Permission records outreach to a publisher or monastery for the right to
+// quote an author.
+type Permission struct {
+	ID            int64            `json:"id"`
+	AuthorID      *int64           `json:"author_id,omitempty"`
+	ContactName   *string          `json:"contact_name,omitempty"`
+	Organization  string           `json:"organization"`
+	Status        PermissionStatus `json:"status"`
+	DateContacted *string          `json:"date_contacted,omitempty"` // YYYY-MM-DD
+	DateResponded *string          `json:"date_responded,omitempty"` // YYYY-MM-DD
+	Notes         *string          `json:"notes,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// PermissionInput is the writable subset of Permission.
+type PermissionInput struct {
+	ContactName   *string          `json:"contact_name"`
+	Organization  string           `json:"organization"`
+	Status        PermissionStatus `json:"status"`
+	DateContacted *string          `json:"date_contacted"`
+	DateResponded *string          `json:"date_responded"`
+	Notes         *string          `json:"notes"`
+}
+
+// Input returns the writable fields of p, e.g. as the base for a PATCH.
+func (p *Permission) Input() PermissionInput {
+	return PermissionInput{
+		ContactName:   p.ContactName,
+		Organization:  p.Organization,
+		Status:        p.Status,
+		DateContacted: p.DateContacted,
+		DateResponded: p.DateResponded,
+		Notes:         p.Notes,
+	}
+}
+
+type APIKeyTier string
+
+const (
+	TierFree       APIKeyTier = "free"
+	TierRegistered APIKeyTier = "registered"
+	TierUnlimited  APIKeyTier = "unlimited"
+	TierAdmin      APIKeyTier = "admin" // May call mutating endpoints; not rate limited
+
+	// TierAnonymous is attached to requests that present no API key.
+	TierAnonymous APIKeyTier = "anonymous"
+)
+
+// Valid reports whether t can be stored on a key; TierAnonymous cannot.
+func (t APIKeyTier) Valid() bool {
+	switch t {
+	case TierFree, TierRegistered, TierUnlimited, TierAdmin:
+		return true
+	}
+	return false
+}
+
+type APIKey struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Email     *string    `json:"email,omitempty"`
+	Tier      APIKeyTier `json:"tier"`
+	RateLimit int        `json:"rate_limit"` // Requests per hour
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+	LastUsed  *time.Time `json:"last_used,omitempty"`
+}
+
+type JobState string
+
+const (
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+const JobKindImageHarvest = "image_harvest"
+
+type Job struct {
+	ID              int64      `json:"id"`
+	Kind            string     `json:"kind"`
+	State           JobState   `json:"state"`
+	ProgressDone    int        `json:"progress_done"`
+	ProgressTotal   int        `json:"progress_total"`
+	Error           *string    `json:"error,omitempty"`
+	CancelRequested bool       `json:"cancel_requested"`
+	CreatedBy       *string    `json:"created_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+
+	Results []JobResult `json:"results,omitempty"`
+}
+
+// JobResult is the outcome of a job for a single author.
+type JobResult struct {
+	AuthorID     int64     `json:"author_id"`
+	AuthorSlug   string    `json:"author_slug"`
+	ImagesStored int       `json:"images_stored"`
+	Error        *string   `json:"error,omitempty"`
+	FinishedAt   time.Time `json:"finished_at"`
+}
+
 // API response types
 
 type PaginatedResponse struct {
@@ -149,15 +446,119 @@ type PaginatedResponse struct {
 }
 
 type QuoteOfTheDay struct {
-	Date   string `json:"date"`
-	Quote  Quote  `json:"quote"`
-	Reason string `json:"reason,omitempty"`
+	Date      string         `json:"date"`
+	Tradition DailyTradition `json:"tradition"`
+	Timezone  string         `json:"timezone,omitempty"`
+	Quote     Quote          `json:"quote"`
+	Reason    string         `json:"reason,omitempty"`
+}
+
+// DailyTradition selects one of the daily quote schedules. DailyAll is the
+// shared schedule served when no tradition is requested.
+type DailyTradition string
+
+const (
+	DailyAll        DailyTradition = "all"
+	DailyOrthodox   DailyTradition = "orthodox"
+	DailyCatholic   DailyTradition = "catholic"
+	DailyProtestant DailyTradition = "protestant"
+	DailyAnglican   DailyTradition = "anglican"
+)
+
+func (t DailyTradition) Valid() bool {
+	switch t {
+	case DailyAll, DailyOrthodox, DailyCatholic, DailyProtestant, DailyAnglican:
+		return true
+	}
+	return false
+}
+
+// AuthorTraditions lists the author traditions whose quotes suit the
+// schedule, or nil for DailyAll, which draws on every author.
+func (t DailyTradition) AuthorTraditions() []AuthorTradition {
+	switch t {
+	case DailyOrthodox:
+		return []AuthorTradition{TraditionPreSchism, TraditionOrthodox}
+	case DailyCatholic:
+		return []AuthorTradition{TraditionPreSchism, TraditionCatholic}
+	case DailyProtestant:
+		return []AuthorTradition{TraditionPreSchism, TraditionProtestant, TraditionAnglican, TraditionNonDenominational}
+	case DailyAnglican:
+		return []AuthorTradition{TraditionPreSchism, TraditionAnglican, TraditionProtestant}
+	}
+	return nil
+}
+
+// DailyScheduleEntry assigns a quote to a date in daily_quotes.
+type DailyScheduleEntry struct {
+	Date    string  `json:"date"` // YYYY-MM-DD
+	QuoteID int64   `json:"quote_id"`
+	Reason  *string `json:"reason,omitempty"`
+}
+
+// CalendarDay lists the authors commemorated on a civil date.
+type CalendarDay struct {
+	Date           string          `json:"date"`
+	JulianDate     string          `json:"julian_date"` // the same day on the Julian calendar
+	Tradition      string          `json:"tradition"`
+	OrthodoxPascha string          `json:"orthodox_pascha"`
+	WesternPascha  string          `json:"western_pascha"`
+	Commemorations []Commemoration `json:"commemorations"`
+}
+
+// Commemoration is an author's feast falling on a CalendarDay.
+type Commemoration struct {
+	Tradition string    `json:"tradition"` // "orthodox" or "catholic"
+	Feast     string    `json:"feast"`     // as recorded on the author
+	Rank      FeastRank `json:"rank"`
+	Author    Author    `json:"author"`
+}
+
+type FeastRank string
+
+const (
+	FeastRankFeast         FeastRank = "feast"         // the author's principal day
+	FeastRankCommemoration FeastRank = "commemoration" // a further day, e.g. a translation of relics
+)
+
+// FeastTitle names a feast of the author called name: "Feast of St. John
+// Chrysostom", or "Commemoration of …" for a further day. Canonized
+// authors get "St." unless name already starts with a title.
+func FeastTitle(name string, canonized bool, rank FeastRank) string {
+	if canonized && !hasSaintTitle(name) {
+		name = "St. " + name
+	}
+	if rank == FeastRankCommemoration {
+		return "Commemoration of " + name
+	}
+	return "Feast of " + name
+}
+
+func hasSaintTitle(name string) bool {
+	for _, t := range []string{"St. ", "St ", "Sts. ", "Saint ", "Saints "} {
+		if strings.HasPrefix(name, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorFeast is a row of author_feasts: a fixed feast (Month and Day on
+// Calendar) or a movable one (PaschaOffset days from Calendar's Pascha).
+type AuthorFeast struct {
+	Tradition    string    `json:"tradition"` // "orthodox" or "catholic"
+	Calendar     string    `json:"calendar"`  // "julian" or "gregorian"
+	Month        *int      `json:"month,omitempty"`
+	Day          *int      `json:"day,omitempty"`
+	PaschaOffset *int      `json:"pascha_offset,omitempty"`
+	Rank         FeastRank `json:"rank"`
 }
 
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-	Code    int    `json:"code,omitempty"`
+	Error   string            `json:"error"`
+	Message string            `json:"message,omitempty"`
+	Code    int               `json:"code,omitempty"`
+	Fields  map[string]string `json:"fields,omitempty"` // Per-field validation errors
 }
 
 type HealthResponse struct {
@@ -174,14 +575,34 @@ type QuoteFilter struct {
 	Tradition  string
 	Verified   *bool
 	Language   string
+	Search     string     // websearch_to_tsquery syntax; results are ordered by rank
+	Translit   bool       // Search also matches Greek originals in Latin transliteration
+	Tier       APIKeyTier // Caller; only admins search the hidden text of restricted quotes
+	Sort       QuoteSort
 	Page       int
 	PerPage    int
 }
 
+// QuoteSort selects the order of quote listings.
+type QuoteSort string
+
+const (
+	SortByID        QuoteSort = ""       // Default: ascending ID
+	SortOldestFirst QuoteSort = "oldest" // Ascending created_at, e.g. for the review queue
+)
+
 type AuthorFilter struct {
 	Era       string
 	Tradition string
 	Search    string
+	Translit  bool // Search also matches Greek names in Latin transliteration
+
+	// Feast on FeastMonth-FeastDay (when set) of FeastCalendar, or of
+	// either calendar when FeastCalendar is empty.
+	FeastMonth    int
+	FeastDay      int
+	FeastCalendar string
+
 	Page      int
 	PerPage   int
 }
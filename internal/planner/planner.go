@@ -0,0 +1,295 @@
+// Package planner fills daily_quotes ahead of time, preferring a quote
+// from a saint commemorated on each day.
+package planner
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/martyria/martyria/internal/db"
+	"github.com/martyria/martyria/internal/models"
+)
+
+const dateLayout = "2006-01-02"
+
+// Options control a planning run.
+type Options struct {
+	From time.Time // first day to plan (date part only)
+	Days int       // horizon
+
+	// Tradition selects the schedule to fill, the authors drawn on and
+	// which calendar's feasts count. Empty means models.DailyAll.
+	Tradition models.DailyTradition
+
+	// A quote is not reused within QuoteWindow days, nor an author within
+	// AuthorWindow days (feasts excepted), unless nothing else is left.
+	QuoteWindow  int
+	AuthorWindow int
+
+	DryRun bool // compute the plan without writing it
+}
+
+// Result summarizes a planning run.
+type Result struct {
+	Tradition models.DailyTradition       `json:"tradition"`
+	From      string                      `json:"from"`
+	To        string                      `json:"to"`
+	Planned   []models.DailyScheduleEntry `json:"planned"`
+	Feasts    int                         `json:"feasts"`  // planned days that honour a feast
+	Kept      int                         `json:"kept"`    // days already scheduled, left as they were
+	Written   int64                       `json:"written"` // rows inserted (0 on dry runs)
+}
+
+// Planner chooses daily quotes from the verified, unrestricted quotes.
+type Planner struct {
+	DB *db.DB
+}
+
+func New(database *db.DB) *Planner {
+	return &Planner{DB: database}
+}
+
+// usage tracks the days on which each quote and author appears: past
+// days as planning advances, plus entries already scheduled ahead.
+type usage struct {
+	quote  map[int64][]int
+	author map[int64][]int
+}
+
+func newUsage() usage {
+	return usage{quote: map[int64][]int{}, author: map[int64][]int{}}
+}
+
+// unbounded is the gap reported for quotes and authors with no other use.
+const unbounded = math.MaxInt32
+
+// record notes a use on day.
+func (u usage) record(day int, quoteID, authorID int64) {
+	u.quote[quoteID] = append(u.quote[quoteID], day)
+	u.author[authorID] = append(u.author[authorID], day)
+}
+
+// gaps returns the distance from day to the nearest other use of the quote
+// and of the author.
+func (u usage) gaps(day int, quoteID, authorID int64) (quoteGap, authorGap int) {
+	return nearest(u.quote[quoteID], day), nearest(u.author[authorID], day)
+}
+
+func nearest(days []int, day int) int {
+	gap := unbounded
+	for _, d := range days {
+		if d == day {
+			continue
+		}
+		if g := max(d-day, day-d); g < gap {
+			gap = g
+		}
+	}
+	return gap
+}
+
+// Plan fills every unscheduled day in [From, From+Days) and, unless
+// DryRun, writes the new entries. Existing entries are never replaced.
+func (p *Planner) Plan(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Days < 1 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+	if opts.Tradition == "" {
+		opts.Tradition = models.DailyAll
+	}
+	from := time.Date(opts.From.Year(), opts.From.Month(), opts.From.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, opts.Days-1)
+
+	cands, err := p.DB.DailyCandidates(ctx, opts.Tradition)
+	if err != nil {
+		return nil, err
+	}
+	if len(cands) == 0 {
+		return nil, fmt.Errorf("no verified quotes to schedule")
+	}
+
+	// Seed usage with what was scheduled in the look-back window so the
+	// first planned days don't repeat recent quotes.
+	lookback := max(opts.QuoteWindow, opts.AuthorWindow)
+	scheduled, err := p.DB.ScheduledQuotes(ctx, opts.Tradition, from.AddDate(0, 0, -lookback), to)
+	if err != nil {
+		return nil, err
+	}
+	res := buildPlan(cands, scheduled, from, opts)
+
+	if !opts.DryRun && len(res.Planned) > 0 {
+		if res.Written, err = p.DB.FillDailySchedule(ctx, opts.Tradition, res.Planned); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// buildPlan assigns a candidate to every day not already in scheduled.
+// Entries of scheduled before from count as recent usage.
+func buildPlan(cands []db.DailyCandidate, scheduled []db.ScheduledQuote, from time.Time, opts Options) *Result {
+	taken := make(map[int]bool, len(scheduled))
+	used := newUsage()
+	for _, s := range scheduled {
+		day := dayIndex(from, s.Date)
+		taken[day] = true
+		used.record(day, s.QuoteID, s.AuthorID)
+	}
+
+	// Day indices within the horizon on which each candidate's author is
+	// commemorated, and the rank of the commemoration on each (a feast
+	// outranks a further commemoration on the same day).
+	feastDays := make([][]int, len(cands))
+	feastRanks := make([]map[int]models.FeastRank, len(cands))
+	for i, c := range cands {
+		feastRanks[i] = map[int]models.FeastRank{}
+		for _, f := range candidateFeasts(c, opts.Tradition) {
+			for day := 0; day < opts.Days; day++ {
+				if !f.Feast.Matches(from.AddDate(0, 0, day)) {
+					continue
+				}
+				if _, ok := feastRanks[i][day]; !ok {
+					feastDays[i] = append(feastDays[i], day)
+					feastRanks[i][day] = f.Rank
+				} else if f.Rank == models.FeastRankFeast {
+					feastRanks[i][day] = f.Rank
+				}
+			}
+		}
+		sort.Ints(feastDays[i])
+	}
+
+	to := from.AddDate(0, 0, opts.Days-1)
+	res := &Result{
+		Tradition: opts.Tradition,
+		From:      from.Format(dateLayout),
+		To:        to.Format(dateLayout),
+		Planned:   []models.DailyScheduleEntry{},
+	}
+	for day := 0; day < opts.Days; day++ {
+		if taken[day] {
+			res.Kept++
+			continue
+		}
+
+		date := from.AddDate(0, 0, day)
+		entry := models.DailyScheduleEntry{Date: date.Format(dateLayout)}
+
+		var onFeast, ordinary []int
+		for i := range cands {
+			switch next := nextFeast(feastDays[i], day); {
+			case next == day:
+				onFeast = append(onFeast, i)
+			case next < 0 || next-day > opts.QuoteWindow:
+				ordinary = append(ordinary, i)
+			}
+		}
+
+		// A saint's own feast outweighs the author window, not the quote window.
+		pick := choose(cands, onFeast, used, day, date, opts.QuoteWindow, 0)
+		if pick >= 0 {
+			c := cands[pick]
+			reason := models.FeastTitle(c.AuthorName, c.Canonized, feastRanks[pick][day])
+			entry.Reason = &reason
+			res.Feasts++
+		} else if pick = choose(cands, ordinary, used, day, date, opts.QuoteWindow, opts.AuthorWindow); pick < 0 {
+			// Only quotes held back for an upcoming feast (or recently used)
+			// remain; take the best of everything.
+			pick = chooseAny(cands, used, day, date, opts)
+		}
+
+		c := cands[pick]
+		entry.QuoteID = c.QuoteID
+		used.record(day, c.QuoteID, c.AuthorID)
+		res.Planned = append(res.Planned, entry)
+	}
+	return res
+}
+
+// chooseAny picks from all candidates, relaxing the author window and then
+// the quote window when every candidate is excluded.
+func chooseAny(cands []db.DailyCandidate, used usage, day int, date time.Time, opts Options) int {
+	all := make([]int, len(cands))
+	for i := range all {
+		all[i] = i
+	}
+	if i := choose(cands, all, used, day, date, opts.QuoteWindow, opts.AuthorWindow); i >= 0 {
+		return i
+	}
+	if i := choose(cands, all, used, day, date, opts.QuoteWindow, 0); i >= 0 {
+		return i
+	}
+	return choose(cands, all, used, day, date, 0, 0)
+}
+
+// choose returns the index (into cands) of the best eligible candidate
+// among idx, or -1. Eligible candidates are not used within the windows
+// around day; the one furthest from any other use wins, with ties broken
+// by a hash of the date so that plans vary but are reproducible.
+func choose(cands []db.DailyCandidate, idx []int, used usage, day int, date time.Time, quoteWindow, authorWindow int) int {
+	best, bestQ, bestA := -1, 0, 0
+	for _, i := range idx {
+		c := cands[i]
+		qGap, aGap := used.gaps(day, c.QuoteID, c.AuthorID)
+		if qGap < quoteWindow || aGap < authorWindow {
+			continue
+		}
+		if best >= 0 {
+			if qGap != bestQ {
+				if qGap < bestQ {
+					continue
+				}
+			} else if aGap != bestA {
+				if aGap < bestA {
+					continue
+				}
+			} else if tieBreak(date, c.QuoteID) >= tieBreak(date, cands[best].QuoteID) {
+				continue
+			}
+		}
+		best, bestQ, bestA = i, qGap, aGap
+	}
+	return best
+}
+
+// nextFeast returns the first of days that is >= day, or -1.
+func nextFeast(days []int, day int) int {
+	for _, d := range days {
+		if d >= day {
+			return d
+		}
+	}
+	return -1
+}
+
+// candidateFeasts returns the commemorations of c's author that tradition
+// keeps: the Orthodox ones for orthodox, the Catholic (Western) ones for
+// the other traditions, and both for the shared schedule.
+func candidateFeasts(c db.DailyCandidate, tradition models.DailyTradition) []db.TraditionFeast {
+	var feasts []db.TraditionFeast
+	for _, f := range c.Feasts {
+		switch {
+		case f.Tradition == "orthodox" && (tradition == models.DailyAll || tradition == models.DailyOrthodox),
+			f.Tradition == "catholic" && tradition != models.DailyOrthodox:
+			feasts = append(feasts, f)
+		}
+	}
+	return feasts
+}
+
+func dayIndex(from, date time.Time) int {
+	d := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(d.Sub(from).Hours() / 24)
+}
+
+func tieBreak(date time.Time, quoteID int64) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(date.Format(dateLayout)))
+	binary.Write(h, binary.LittleEndian, quoteID)
+	return h.Sum64()
+}
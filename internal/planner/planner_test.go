@@ -0,0 +1,172 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/martyria/martyria/internal/calendar"
+	"github.com/martyria/martyria/internal/db"
+	"github.com/martyria/martyria/internal/models"
+)
+
+var (
+	// Chrysostom: November 13 on the Julian calendar, the 26th civil.
+	chrysostom = []db.TraditionFeast{{
+		Tradition: "orthodox",
+		Rank:      models.FeastRankFeast,
+		Feast:     calendar.Feast{Calendar: calendar.Julian, Month: time.November, Day: 13},
+	}}
+	// And September 13 on the Roman calendar.
+	chrysostomRoman = []db.TraditionFeast{{
+		Tradition: "catholic",
+		Rank:      models.FeastRankFeast,
+		Feast:     calendar.Feast{Calendar: calendar.Gregorian, Month: time.September, Day: 13},
+	}}
+	// A further commemoration on November 22 (Gregorian).
+	translation = []db.TraditionFeast{{
+		Tradition: "catholic",
+		Rank:      models.FeastRankCommemoration,
+		Feast:     calendar.Feast{Calendar: calendar.Gregorian, Month: time.November, Day: 22},
+	}}
+)
+
+// candidates returns n quotes by n different authors without feasts,
+// with IDs and author IDs from 100.
+func candidates(n int) []db.DailyCandidate {
+	var cands []db.DailyCandidate
+	for i := 0; i < n; i++ {
+		cands = append(cands, db.DailyCandidate{QuoteID: int64(100 + i), AuthorID: int64(100 + i), AuthorName: "Author"})
+	}
+	return cands
+}
+
+func TestBuildPlan(t *testing.T) {
+	from := time.Date(2026, time.November, 20, 0, 0, 0, 0, time.UTC)
+	saint := db.DailyCandidate{QuoteID: 1, AuthorID: 1, AuthorName: "John Chrysostom", Canonized: true, Feasts: chrysostom}
+	romanSaint := db.DailyCandidate{QuoteID: 1, AuthorID: 1, AuthorName: "John Chrysostom", Canonized: true, Feasts: chrysostomRoman}
+
+	tests := []struct {
+		name      string
+		from      time.Time // zero: November 20
+		cands     []db.DailyCandidate
+		scheduled []db.ScheduledQuote
+		opts      Options
+		feasts    map[string]int64 // date -> quote expected with a feast reason
+		reason    string           // expected on every feast day
+		kept      int
+	}{
+		{
+			name:   "julian feast",
+			cands:  append(candidates(10), saint),
+			opts:   Options{Days: 10, Tradition: models.DailyOrthodox, QuoteWindow: 5, AuthorWindow: 3},
+			feasts: map[string]int64{"2026-11-26": 1},
+			reason: "Feast of St. John Chrysostom",
+		},
+		{
+			name:   "feast not kept by tradition",
+			cands:  append(candidates(10), saint),
+			opts:   Options{Days: 10, Tradition: models.DailyCatholic, QuoteWindow: 5, AuthorWindow: 3},
+			feasts: map[string]int64{},
+		},
+		{
+			name:   "western feast",
+			from:   time.Date(2026, time.September, 10, 0, 0, 0, 0, time.UTC),
+			cands:  append(candidates(10), romanSaint),
+			opts:   Options{Days: 10, Tradition: models.DailyCatholic, QuoteWindow: 5, AuthorWindow: 3},
+			feasts: map[string]int64{"2026-09-13": 1},
+			reason: "Feast of St. John Chrysostom",
+		},
+		{
+			name:   "name already titled",
+			cands:  append(candidates(10), db.DailyCandidate{QuoteID: 1, AuthorID: 1, AuthorName: "St. John Chrysostom", Canonized: true, Feasts: chrysostom}),
+			opts:   Options{Days: 10, Tradition: models.DailyOrthodox, QuoteWindow: 5, AuthorWindow: 3},
+			feasts: map[string]int64{"2026-11-26": 1},
+			reason: "Feast of St. John Chrysostom",
+		},
+		{
+			name:   "commemoration of an author not canonized",
+			cands:  append(candidates(10), db.DailyCandidate{QuoteID: 1, AuthorID: 1, AuthorName: "Thomas à Kempis", Feasts: translation}),
+			opts:   Options{Days: 10, Tradition: models.DailyCatholic, QuoteWindow: 5, AuthorWindow: 3},
+			feasts: map[string]int64{"2026-11-22": 1},
+			reason: "Commemoration of Thomas à Kempis",
+		},
+		{
+			name:   "western feast not kept by orthodox",
+			from:   time.Date(2026, time.September, 10, 0, 0, 0, 0, time.UTC),
+			cands:  append(candidates(10), romanSaint),
+			opts:   Options{Days: 10, Tradition: models.DailyOrthodox, QuoteWindow: 5, AuthorWindow: 3},
+			feasts: map[string]int64{},
+		},
+		{
+			name:  "existing entries kept",
+			cands: append(candidates(10), saint),
+			scheduled: []db.ScheduledQuote{
+				{Date: from.AddDate(0, 0, -1), QuoteID: 100, AuthorID: 100},
+				{Date: time.Date(2026, time.November, 26, 0, 0, 0, 0, time.UTC), QuoteID: 101, AuthorID: 101},
+			},
+			opts:   Options{Days: 10, Tradition: models.DailyAll, QuoteWindow: 5, AuthorWindow: 3},
+			feasts: map[string]int64{},
+			kept:   1,
+		},
+		{
+			name:   "too few quotes for the window",
+			cands:  candidates(2),
+			opts:   Options{Days: 7, Tradition: models.DailyAll, QuoteWindow: 5, AuthorWindow: 3},
+			feasts: map[string]int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := from
+			if !tt.from.IsZero() {
+				start = tt.from
+			}
+			res := buildPlan(tt.cands, tt.scheduled, start, tt.opts)
+
+			if res.Kept != tt.kept {
+				t.Errorf("Kept = %d, want %d", res.Kept, tt.kept)
+			}
+			if got := len(res.Planned) + res.Kept; got != tt.opts.Days {
+				t.Errorf("planned %d and kept %d days, want %d in all", len(res.Planned), res.Kept, tt.opts.Days)
+			}
+			if res.Feasts != len(tt.feasts) {
+				t.Errorf("Feasts = %d, want %d", res.Feasts, len(tt.feasts))
+			}
+
+			lastUse := map[int64]time.Time{}
+			for _, s := range tt.scheduled {
+				lastUse[s.QuoteID] = s.Date
+			}
+			enough := len(tt.cands) > tt.opts.QuoteWindow
+			for _, e := range res.Planned {
+				date, _ := time.Parse(dateLayout, e.Date)
+				if want, ok := tt.feasts[e.Date]; ok {
+					if e.QuoteID != want || e.Reason == nil {
+						t.Errorf("%s: quote %d (reason %v), want feast quote %d", e.Date, e.QuoteID, e.Reason, want)
+					} else if *e.Reason != tt.reason {
+						t.Errorf("%s: reason %q, want %q", e.Date, *e.Reason, tt.reason)
+					}
+				} else if e.Reason != nil {
+					t.Errorf("%s: unexpected reason %q", e.Date, *e.Reason)
+				}
+				if prev, ok := lastUse[e.QuoteID]; ok && enough && date.Sub(prev) < time.Duration(tt.opts.QuoteWindow)*24*time.Hour {
+					t.Errorf("%s: quote %d repeated within %d days (last %s)", e.Date, e.QuoteID, tt.opts.QuoteWindow, prev.Format(dateLayout))
+				}
+				lastUse[e.QuoteID] = date
+			}
+		})
+	}
+}
+
+func TestBuildPlanReproducible(t *testing.T) {
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	opts := Options{Days: 30, Tradition: models.DailyAll, QuoteWindow: 7, AuthorWindow: 3}
+	a := buildPlan(candidates(20), nil, from, opts)
+	b := buildPlan(candidates(20), nil, from, opts)
+	for i := range a.Planned {
+		if a.Planned[i].QuoteID != b.Planned[i].QuoteID {
+			t.Fatalf("%s: quote %d, then %d", a.Planned[i].Date, a.Planned[i].QuoteID, b.Planned[i].QuoteID)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package storage abstracts where image files live: the local filesystem
+// or an S3-compatible object store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/martyria/martyria/internal/config"
+)
+
+// ErrNotFound is returned by Get when no object exists for the key.
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores objects by slash-separated key, e.g.
+// "john-chrysostom/thumbs/Icon.jpg". Keys are what images.local_path and
+// images.thumbnail_path record.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// URL returns a URL clients can fetch the object from: a public or
+	// presigned URL for S3, or the API's /data/images route for local disk.
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// FromConfig builds the backend selected by STORAGE_BACKEND ("local" or "s3").
+func FromConfig(ctx context.Context, cfg *config.Config) (Storage, error) {
+	switch cfg.StorageBackend {
+	case "", "local":
+		return NewLocal(cfg.ImageDir, cfg.BaseURL+"/data/images"), nil
+	case "s3":
+		return NewS3(ctx, S3Options{
+			Endpoint:      cfg.S3Endpoint,
+			Region:        cfg.S3Region,
+			Bucket:        cfg.S3Bucket,
+			AccessKey:     cfg.S3AccessKey,
+			SecretKey:     cfg.S3SecretKey,
+			UseSSL:        cfg.S3UseSSL,
+			PublicURL:     cfg.S3PublicURL,
+			PresignExpiry: cfg.S3PresignExpiry,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (want local or s3)", cfg.StorageBackend)
+	}
+}
+
+// joinURL appends an escaped key to base.
+func joinURL(base, key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,150 @@
+// Package textfold reduces Greek and Latin text to search keys: Fold
+// drops accents, breathings and iota subscripts and normalizes letter
+// forms, and Transliterate additionally spells Greek in Latin letters, so
+// that "hesychia" and ἡσυχία share a key.
+package textfold
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	roughBreathing = '̔' // dasia
+	diaeresis      = '̈'
+)
+
+// Fold returns s lowercased, without combining marks (accents, breathings,
+// iota subscripts, diaereses), in compatibility-decomposed form (so ϐ, ϑ
+// and other variant letters become their plain forms), with final and
+// lunate sigma as σ and the Latin ligatures æ and œ spelled out.
+func Fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		switch r = unicode.ToLower(r); r {
+		case 'ς':
+			r = 'σ'
+		case 'æ':
+			b.WriteString("ae")
+			continue
+		case 'œ':
+			b.WriteString("oe")
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// letter is a base character with the combining marks that follow it.
+type letter struct {
+	base  rune
+	marks []rune
+}
+
+func (l letter) has(mark rune) bool {
+	for _, m := range l.marks {
+		if m == mark {
+			return true
+		}
+	}
+	return false
+}
+
+// Transliterate returns Fold(s) with Greek letters spelled in Latin ones
+// after the usual scholarly conventions, without length marks: η and ω
+// become e and o, υ is y except in the diphthongs au, eu, ou, γ before
+// a velar is n, initial and doubled ρ are rh and rrh, and the rough
+// breathing is h (ἁγίων: hagion, οἱ: hoi). Latin-script text comes back
+// as Fold would return it.
+func Transliterate(s string) string {
+	var letters []letter
+	for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+		if unicode.Is(unicode.Mn, r) && len(letters) > 0 {
+			last := &letters[len(letters)-1]
+			last.marks = append(last.marks, r)
+			continue
+		}
+		if r == 'ς' {
+			r = 'σ'
+		}
+		letters = append(letters, letter{base: r})
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(letters); {
+		if !unicode.IsLetter(letters[start].base) {
+			b.WriteRune(letters[start].base)
+			start++
+			continue
+		}
+		end := start
+		for end < len(letters) && unicode.IsLetter(letters[end].base) {
+			end++
+		}
+		writeWord(&b, letters[start:end])
+		start = end
+	}
+	return Fold(b.String())
+}
+
+// writeWord transliterates one word. The rough breathing only occurs at
+// the start of a word, on its first vowel or the second of a diphthong.
+func writeWord(b *strings.Builder, word []letter) {
+	for i := 0; i < len(word) && i < 2; i++ {
+		if word[i].has(roughBreathing) && isGreekVowel(word[i].base) {
+			b.WriteByte('h')
+			break
+		}
+	}
+
+	for i, l := range word {
+		var next rune
+		if i+1 < len(word) {
+			next = word[i+1].base
+		}
+		switch l.base {
+		case 'γ':
+			if strings.ContainsRune("γκξχ", next) {
+				b.WriteByte('n')
+			} else {
+				b.WriteByte('g')
+			}
+		case 'ρ':
+			if i == 0 || l.has(roughBreathing) || word[i-1].base == 'ρ' {
+				b.WriteString("rh")
+			} else {
+				b.WriteByte('r')
+			}
+		case 'υ':
+			if i > 0 && strings.ContainsRune("αεηο", word[i-1].base) && !l.has(diaeresis) {
+				b.WriteByte('u')
+			} else {
+				b.WriteByte('y')
+			}
+		default:
+			if t, ok := greekLatin[l.base]; ok {
+				b.WriteString(t)
+			} else {
+				b.WriteRune(l.base)
+			}
+		}
+	}
+}
+
+func isGreekVowel(r rune) bool {
+	return strings.ContainsRune("αεηιουω", r)
+}
+
+// greekLatin spells the Greek letters not handled in writeWord.
+var greekLatin = map[rune]string{
+	'α': "a", 'β': "b", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "e",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n",
+	'ξ': "x", 'ο': "o", 'π': "p", 'σ': "s", 'τ': "t", 'φ': "ph",
+	'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
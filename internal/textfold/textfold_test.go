@@ -0,0 +1,48 @@
+package textfold
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"ἡσυχία", "ησυχια"},
+		{"Ἡσυχία", "ησυχια"},
+		{"ΘΕΟΣ", "θεοσ"},
+		{"λόγος", "λογοσ"},
+		{"ᾠδῇ", "ωδη"},       // iota subscripts
+		{"ϲοφία", "σοφια"},   // lunate sigma
+		{"\u1f71", "α"},      // alpha with oxia, canonically a tonos
+		{"ϐίος", "βιοσ"},     // compatibility form of beta
+		{"προϊών", "προιων"}, // diaeresis
+		{"Lætitia cœli", "laetitia coeli"},
+		{"Gloria Patri", "gloria patri"},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"ἡσυχία", "hesychia"},
+		{"λόγος", "logos"},
+		{"θέωσις", "theosis"},
+		{"Κύριε ἐλέησον", "kyrie eleeson"},
+		{"οἱ ἅγιοι", "hoi hagioi"}, // rough breathing on a diphthong
+		{"ὕδωρ", "hydor"},
+		{"ῥῆμα", "rhema"},
+		{"Πυρρός", "pyrrhos"},
+		{"εὐχή", "euche"},
+		{"ἄγγελος", "angelos"},
+		{"Ἰωάννης ὁ Χρυσόστομος", "ioannes ho chrysostomos"},
+		{"ψυχή", "psyche"},
+		{"Lætitia", "laetitia"},
+		{`"ἀγάπη" -φόβος`, `"agape" -phobos`}, // search syntax is kept
+	}
+	for _, tt := range tests {
+		if got := Transliterate(tt.in); got != tt.want {
+			t.Errorf("Transliterate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
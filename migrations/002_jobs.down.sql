@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS jobs_updated_at ON jobs;
+
+DROP TABLE IF EXISTS job_results;
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,31 @@
+-- Tracked background jobs (e.g. image harvests)
+
+CREATE TABLE jobs (
+    id                  BIGSERIAL PRIMARY KEY,
+    kind                TEXT NOT NULL,              -- e.g. 'image_harvest'
+    state               TEXT NOT NULL DEFAULT 'running', -- 'running', 'succeeded', 'failed', 'cancelled'
+    progress_done       INTEGER NOT NULL DEFAULT 0,
+    progress_total      INTEGER NOT NULL DEFAULT 0,
+    error               TEXT,
+    cancel_requested    BOOLEAN NOT NULL DEFAULT false,
+    created_by          TEXT,                       -- API key name
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now(), -- Doubles as heartbeat
+    finished_at         TIMESTAMPTZ
+);
+
+-- At most one running job per kind (prevents concurrent full harvests)
+CREATE UNIQUE INDEX idx_jobs_running_kind ON jobs(kind) WHERE state = 'running';
+
+-- Per-author outcome of a harvest job
+CREATE TABLE job_results (
+    job_id          BIGINT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    author_id       BIGINT NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+    images_stored   INTEGER NOT NULL DEFAULT 0,
+    error           TEXT,
+    finished_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (job_id, author_id)
+);
+
+CREATE TRIGGER jobs_updated_at BEFORE UPDATE ON jobs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_permissions_author_status;
+
+ALTER TABLE permissions DROP CONSTRAINT IF EXISTS permissions_status_check;
@@ -0,0 +1,8 @@
+-- Permissions: constrain status and support per-author lookups, which the
+-- quote gating in internal/db runs on every quote query.
+
+ALTER TABLE permissions
+    ADD CONSTRAINT permissions_status_check
+    CHECK (status IN ('pending', 'granted', 'denied', 'no_response'));
+
+CREATE INDEX idx_permissions_author_status ON permissions(author_id, status);
@@ -0,0 +1,5 @@
+DELETE FROM daily_quotes WHERE tradition <> 'all';
+
+ALTER TABLE daily_quotes DROP CONSTRAINT daily_quotes_pkey;
+ALTER TABLE daily_quotes DROP COLUMN tradition;
+ALTER TABLE daily_quotes ADD PRIMARY KEY (date);
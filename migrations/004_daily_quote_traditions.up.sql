@@ -0,0 +1,10 @@
+-- Daily quotes: one schedule per tradition. Existing rows become the
+-- shared 'all' schedule, served when no tradition is requested.
+
+ALTER TABLE daily_quotes
+    ADD COLUMN tradition TEXT NOT NULL DEFAULT 'all',
+    ADD CONSTRAINT daily_quotes_tradition_check
+    CHECK (tradition IN ('all', 'orthodox', 'catholic', 'protestant', 'anglican'));
+
+ALTER TABLE daily_quotes DROP CONSTRAINT daily_quotes_pkey;
+ALTER TABLE daily_quotes ADD PRIMARY KEY (tradition, date);
@@ -0,0 +1,26 @@
+-- Author feasts: structured feast days, one row per commemoration.
+-- Fixed feasts have a month and day on their calendar; movable feasts an
+-- offset in days from that calendar's Pascha (Julian: Orthodox, Gregorian:
+-- Western). Rows are derived from feast_day_orthodox (Julian) and
+-- feast_day_catholic (Gregorian); existing authors are backfilled by the
+-- server at startup, since the strings are parsed in Go.
+
+CREATE TABLE author_feasts (
+    id              BIGSERIAL PRIMARY KEY,
+    author_id       BIGINT NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+    tradition       TEXT NOT NULL CHECK (tradition IN ('orthodox', 'catholic')),
+    calendar        TEXT NOT NULL CHECK (calendar IN ('julian', 'gregorian')),
+    month           SMALLINT CHECK (month BETWEEN 1 AND 12),
+    day             SMALLINT CHECK (day BETWEEN 1 AND 31),
+    pascha_offset   SMALLINT,
+    rank            TEXT NOT NULL DEFAULT 'feast'
+                    CHECK (rank IN ('feast', 'commemoration')),  -- principal day, or a further one (e.g. relics)
+    CONSTRAINT author_feasts_fixed_or_movable CHECK (
+        (month IS NOT NULL AND day IS NOT NULL AND pascha_offset IS NULL)
+        OR (month IS NULL AND day IS NULL AND pascha_offset IS NOT NULL)
+    )
+);
+
+CREATE INDEX idx_author_feasts_author ON author_feasts(author_id);
+CREATE INDEX idx_author_feasts_date ON author_feasts(calendar, month, day);
+CREATE INDEX idx_author_feasts_movable ON author_feasts(calendar, pascha_offset) WHERE pascha_offset IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_quotes_search;
+ALTER TABLE quotes DROP COLUMN IF EXISTS search_vector;
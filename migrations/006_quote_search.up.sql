@@ -0,0 +1,11 @@
+-- Quote search: a weighted tsvector over the English text (A) and the
+-- source work's title (B), kept up to date by Postgres as a generated
+-- column and indexed for websearch_to_tsquery matches.
+
+ALTER TABLE quotes ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(text, '')), 'A')
+        || setweight(to_tsvector('english', coalesce(source_work, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_quotes_search ON quotes USING GIN (search_vector);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_authors_name_original_latin;
+DROP INDEX IF EXISTS idx_authors_name_original_folded;
+DROP INDEX IF EXISTS idx_quotes_original_latin;
+DROP INDEX IF EXISTS idx_quotes_original_folded;
+
+ALTER TABLE authors
+    DROP COLUMN IF EXISTS name_original_latin,
+    DROP COLUMN IF EXISTS name_original_folded;
+
+ALTER TABLE quotes
+    DROP COLUMN IF EXISTS text_original_latin,
+    DROP COLUMN IF EXISTS text_original_folded;
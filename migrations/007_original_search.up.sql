@@ -0,0 +1,23 @@
+-- Original-language search: search keys for quotes.text_original and
+-- authors.name_original. The _folded columns drop accents, breathings and
+-- iota subscripts and normalize letter forms (ἡσυχία: ησυχια); the _latin
+-- columns also transliterate Greek (hesychia). Both are computed in Go
+-- (internal/textfold) on every write; existing rows are backfilled by the
+-- server at startup.
+
+ALTER TABLE quotes
+    ADD COLUMN text_original_folded TEXT,
+    ADD COLUMN text_original_latin  TEXT;
+
+ALTER TABLE authors
+    ADD COLUMN name_original_folded TEXT,
+    ADD COLUMN name_original_latin  TEXT;
+
+CREATE INDEX idx_quotes_original_folded ON quotes USING GIN (to_tsvector('simple', text_original_folded));
+CREATE INDEX idx_quotes_original_latin ON quotes USING GIN (to_tsvector('simple', text_original_latin));
+
+-- Author names are matched as substrings (LIKE '%…%'), like name and bio.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_authors_name_original_folded ON authors USING GIN (name_original_folded gin_trgm_ops);
+CREATE INDEX idx_authors_name_original_latin ON authors USING GIN (name_original_latin gin_trgm_ops);
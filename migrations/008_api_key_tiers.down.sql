@@ -0,0 +1 @@
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_tier_check;
@@ -0,0 +1,5 @@
+-- API key tiers: only the tiers the API knows may be stored. Keys with
+-- any other tier must be updated before applying.
+
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_tier_check
+    CHECK (tier IN ('free', 'registered', 'unlimited', 'admin'));
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS authors_updated_at ON authors;
+CREATE TRIGGER authors_updated_at BEFORE UPDATE ON authors
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+DROP TRIGGER IF EXISTS quotes_updated_at ON quotes;
+CREATE TRIGGER quotes_updated_at BEFORE UPDATE ON quotes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,21 @@
+-- Search keys and updated_at: computing the original-language search keys
+-- (migration 007) is not a content edit, so updated_at is left alone when
+-- an update changes only the _folded/_latin columns. Writes that change
+-- the original, or anything while leaving the keys as they were, still
+-- bump it.
+
+DROP TRIGGER IF EXISTS quotes_updated_at ON quotes;
+CREATE TRIGGER quotes_updated_at BEFORE UPDATE ON quotes
+    FOR EACH ROW
+    WHEN (OLD.text_original IS DISTINCT FROM NEW.text_original
+        OR (OLD.text_original_folded IS NOT DISTINCT FROM NEW.text_original_folded
+            AND OLD.text_original_latin IS NOT DISTINCT FROM NEW.text_original_latin))
+    EXECUTE FUNCTION update_updated_at();
+
+DROP TRIGGER IF EXISTS authors_updated_at ON authors;
+CREATE TRIGGER authors_updated_at BEFORE UPDATE ON authors
+    FOR EACH ROW
+    WHEN (OLD.name_original IS DISTINCT FROM NEW.name_original
+        OR (OLD.name_original_folded IS NOT DISTINCT FROM NEW.name_original_folded
+            AND OLD.name_original_latin IS NOT DISTINCT FROM NEW.name_original_latin))
+    EXECUTE FUNCTION update_updated_at();
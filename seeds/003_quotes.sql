@@ -1,12 +1,16 @@
 -- Seed data: Quotes
 -- Initial verified quotes from public domain / fair use sources.
 -- Each quote includes the source work for verification.
+-- Quotes already present (same author and text) are skipped and topic links
+-- ignore duplicates, so the file can be re-applied safely.
 
 -- =============================================
 -- APOSTOLIC FATHERS
 -- =============================================
 
-INSERT INTO quotes (author_id, text, language, source_work, source_chapter, license, verified) VALUES
+INSERT INTO quotes (author_id, text, language, source_work, source_chapter, license, verified)
+SELECT v.author_id, v.text, v.language, v.source_work, v.source_chapter, v.license, v.verified
+FROM (VALUES
 
 -- Clement of Rome
 ((SELECT id FROM authors WHERE slug = 'clement-of-rome'),
@@ -303,7 +307,10 @@ INSERT INTO quotes (author_id, text, language, source_work, source_chapter, lice
 ((SELECT id FROM authors WHERE slug = 'justin-popovic'),
  'Only through Christ does man truly become man. Without Him, man is but a caricature of himself.',
  'en', 'Philosophical Abysses', NULL, 'short_quote_fair_use', true)
-;
+) AS v (author_id, text, language, source_work, source_chapter, license, verified)
+WHERE NOT EXISTS (
+    SELECT 1 FROM quotes q WHERE q.author_id = v.author_id AND q.text = v.text
+);
 
 -- =============================================
 -- Tag quotes with topics
@@ -312,78 +319,94 @@ INSERT INTO quotes (author_id, text, language, source_work, source_chapter, lice
 -- Salvation & Theosis quotes
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE q.text LIKE '%became man%God%' AND t.slug = 'salvation';
+WHERE q.text LIKE '%became man%God%' AND t.slug = 'salvation'
+ON CONFLICT DO NOTHING;
 
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE q.text LIKE '%man fully alive%' AND t.slug = 'salvation';
+WHERE q.text LIKE '%man fully alive%' AND t.slug = 'salvation'
+ON CONFLICT DO NOTHING;
 
 -- Prayer
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%pray%' OR q.text LIKE '%prayer%') AND t.slug = 'prayer';
+WHERE (q.text LIKE '%pray%' OR q.text LIKE '%prayer%') AND t.slug = 'prayer'
+ON CONFLICT DO NOTHING;
 
 -- Love
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%love%' OR q.text LIKE '%charity%') AND t.slug = 'love';
+WHERE (q.text LIKE '%love%' OR q.text LIKE '%charity%') AND t.slug = 'love'
+ON CONFLICT DO NOTHING;
 
 -- Repentance
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%repentance%' OR q.text LIKE '%repent%') AND t.slug = 'repentance';
+WHERE (q.text LIKE '%repentance%' OR q.text LIKE '%repent%') AND t.slug = 'repentance'
+ON CONFLICT DO NOTHING;
 
 -- Faith
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE q.text LIKE '%faith%' AND t.slug = 'faith';
+WHERE q.text LIKE '%faith%' AND t.slug = 'faith'
+ON CONFLICT DO NOTHING;
 
 -- Humility
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%humility%' OR q.text LIKE '%humble%') AND t.slug = 'humility';
+WHERE (q.text LIKE '%humility%' OR q.text LIKE '%humble%') AND t.slug = 'humility'
+ON CONFLICT DO NOTHING;
 
 -- Church
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%Church%' OR q.text LIKE '%bishop%') AND t.slug = 'church';
+WHERE (q.text LIKE '%Church%' OR q.text LIKE '%bishop%') AND t.slug = 'church'
+ON CONFLICT DO NOTHING;
 
 -- Scripture
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%Scripture%' OR q.text LIKE '%Bible%' OR q.text LIKE '%Word of God%') AND t.slug = 'scripture';
+WHERE (q.text LIKE '%Scripture%' OR q.text LIKE '%Bible%' OR q.text LIKE '%Word of God%') AND t.slug = 'scripture'
+ON CONFLICT DO NOTHING;
 
 -- Suffering
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%suffer%' OR q.text LIKE '%martyr%' OR q.text LIKE '%despair%') AND t.slug = 'suffering';
+WHERE (q.text LIKE '%suffer%' OR q.text LIKE '%martyr%' OR q.text LIKE '%despair%') AND t.slug = 'suffering'
+ON CONFLICT DO NOTHING;
 
 -- Peace
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%peace%' OR q.text LIKE '%stillness%') AND t.slug = 'peace';
+WHERE (q.text LIKE '%peace%' OR q.text LIKE '%stillness%') AND t.slug = 'peace'
+ON CONFLICT DO NOTHING;
 
 -- Eucharist
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%chalice%' OR q.text LIKE '%bread%' OR q.text LIKE '%Body%Blood%') AND t.slug = 'eucharist';
+WHERE (q.text LIKE '%chalice%' OR q.text LIKE '%bread%' OR q.text LIKE '%Body%Blood%') AND t.slug = 'eucharist'
+ON CONFLICT DO NOTHING;
 
 -- Icons
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%matter%worship%' OR q.text LIKE '%icon%') AND t.slug = 'icons';
+WHERE (q.text LIKE '%matter%worship%' OR q.text LIKE '%icon%') AND t.slug = 'icons'
+ON CONFLICT DO NOTHING;
 
 -- Wisdom
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%wisdom%' OR q.text LIKE '%knowledge%' OR q.text LIKE '%thoughts%') AND t.slug = 'wisdom';
+WHERE (q.text LIKE '%wisdom%' OR q.text LIKE '%knowledge%' OR q.text LIKE '%thoughts%') AND t.slug = 'wisdom'
+ON CONFLICT DO NOTHING;
 
 -- Virtue
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%virtue%' OR q.text LIKE '%holiness%') AND t.slug = 'virtue';
+WHERE (q.text LIKE '%virtue%' OR q.text LIKE '%holiness%') AND t.slug = 'virtue'
+ON CONFLICT DO NOTHING;
 
 -- Sin & Temptation
 INSERT INTO quote_topics (quote_id, topic_id)
 SELECT q.id, t.id FROM quotes q, topics t
-WHERE (q.text LIKE '%sin%' OR q.text LIKE '%temptation%') AND t.slug = 'sin';
+WHERE (q.text LIKE '%sin%' OR q.text LIKE '%temptation%') AND t.slug = 'sin'
+ON CONFLICT DO NOTHING;
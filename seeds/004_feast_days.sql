@@ -0,0 +1,52 @@
+-- Seed data: Feast days
+-- feast_day_orthodox is "<Julian (Old) calendar church date> / <its Gregorian (civil,
+-- new-style) equivalent>"; only the first date is parsed. Further commemorations
+-- follow after a semicolon.
+-- feast_day_catholic is the date in the General Roman Calendar (or Roman Martyrology).
+
+UPDATE authors SET feast_day_orthodox = v.orthodox, feast_day_catholic = v.catholic
+FROM (VALUES
+    -- Apostolic & Ante-Nicene
+    ('clement-of-rome',           'November 25 / December 8',  'November 23'),
+    ('ignatius-of-antioch',       'December 20 / January 2',   'October 17'),
+    ('polycarp-of-smyrna',        'February 23 / March 8',     'February 23'),
+    ('irenaeus-of-lyon',          'August 23 / September 5',   'June 28'),
+    ('justin-martyr',             'June 1 / June 14',          'June 1'),
+    ('cyprian-of-carthage',       'August 31 / September 13',  'September 16'),
+
+    -- Nicene & Post-Nicene
+    ('athanasius-of-alexandria',  'January 18 / January 31; May 2 / May 15', 'May 2'),
+    ('basil-the-great',           'January 1 / January 14',    'January 2'),
+    ('gregory-nazianzen',         'January 25 / February 7',   'January 2'),
+    ('gregory-of-nyssa',          'January 10 / January 23',   'January 10'),
+    ('john-chrysostom',           'November 13 / November 26', 'September 13'),
+    ('augustine-of-hippo',        'June 15 / June 28',         'August 28'),
+    ('jerome',                    'June 15 / June 28',         'September 30'),
+    ('cyril-of-alexandria',       'June 9 / June 22',          'June 27'),
+    ('maximus-the-confessor',     'January 21 / February 3',   'August 13'),
+    ('john-of-damascus',          'December 4 / December 17',  'December 4'),
+    ('ephrem-the-syrian',         'January 28 / February 10',  'June 9'),
+    ('john-cassian',              'February 29 / March 13',    'July 23'),
+
+    -- Medieval
+    ('symeon-new-theologian',     'March 12 / March 25',       NULL),
+    ('gregory-palamas',           'November 14 / November 27; Second Sunday of Great Lent', NULL),
+    ('thomas-aquinas',            NULL,                        'January 28'),
+    ('francis-of-assisi',         NULL,                        'October 4'),
+
+    -- Modern saints
+    ('theophanes-the-recluse',    'January 10 / January 23',   NULL),
+    ('seraphim-of-sarov',         'January 2 / January 15; July 19 / August 1', NULL),
+    ('paisios-of-mount-athos',    'July 12 / July 25',         NULL),
+    ('porphyrios-of-kavsokalyvia','December 2 / December 15',  NULL),
+    ('nektarios-of-aegina',       'November 9 / November 22',  NULL),
+    ('sophrony-of-essex',         'July 11 / July 24',         NULL),
+    ('silouan-the-athonite',      'September 24 / October 7',  NULL),
+    ('john-maximovitch',          'June 19 / July 2',          NULL),
+    ('nikolaj-velimirovic',       'March 5 / March 18',        NULL),
+    ('justin-popovic',            'June 1 / June 14',          NULL),
+    ('joseph-the-hesychast',      'August 16 / August 29',     NULL),
+    ('philaret-of-moscow',        'November 19 / December 2',  NULL),
+    ('john-of-kronstadt',         'December 20 / January 2',   NULL)
+) AS v(slug, orthodox, catholic)
+WHERE authors.slug = v.slug;